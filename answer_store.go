@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// storeVersion 本地题库文件格式版本
+// 版本1: 单一题库 answers
+// 版本2: 多个命名题库 banks
+const storeVersion = 2
+
+// storeFileName 本地题库文件名
+const storeFileName = "answers.json"
+
+// defaultBankName 未指定名称时使用的题库名
+const defaultBankName = "默认题库"
+
+// BankInfo 题库信息
+type BankInfo struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`       // 题库名称
+	SourceFile string    `json:"sourceFile"` // 导入来源文件
+	ImportedAt time.Time `json:"importedAt"` // 导入时间
+	ItemCount  int       `json:"itemCount"`  // 题目数量
+	Enabled    bool      `json:"enabled"`    // 是否参与搜索
+	IsDefault  bool      `json:"isDefault"`  // 是否为 SetGlobalAnswers 写入的默认题库
+}
+
+// storedBank 题库信息及其题目
+type storedBank struct {
+	BankInfo
+	Answers []AnswerItem `json:"answers"`
+
+	index *searchIndex // 搜索用倒排索引，导入和加载时建立
+}
+
+// storeData 本地题库文件内容
+type storeData struct {
+	Version   int          `json:"version"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+	Banks     []storedBank `json:"banks"`
+	Answers   []AnswerItem `json:"answers,omitempty"` // 仅用于读取版本1的文件
+}
+
+// AnswerStore 题库本地存储，数据保存在用户配置目录下，应用重启后自动加载
+type AnswerStore struct {
+	mu    sync.RWMutex
+	path  string
+	banks []storedBank
+}
+
+// 全局题库存储
+var answerStore = NewAnswerStore(defaultStorePath())
+
+// appConfigDir 返回应用配置目录
+func appConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		// 无法获取配置目录时退回到当前目录
+		dir = "."
+	}
+	return filepath.Join(dir, "exam_assistant")
+}
+
+// defaultStorePath 返回默认的题库文件路径
+func defaultStorePath() string {
+	return filepath.Join(appConfigDir(), storeFileName)
+}
+
+// NewAnswerStore 创建题库存储
+func NewAnswerStore(path string) *AnswerStore {
+	return &AnswerStore{path: path}
+}
+
+// Path 返回题库文件路径
+func (s *AnswerStore) Path() string {
+	return s.path
+}
+
+// Load 从磁盘加载题库，文件不存在时视为空题库
+func (s *AnswerStore) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.banks = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取题库文件失败: %v", err)
+	}
+
+	var data storeData
+	if err := json.Unmarshal(content, &data); err != nil {
+		return fmt.Errorf("解析题库文件失败: %v", err)
+	}
+	if data.Version > storeVersion {
+		return fmt.Errorf("题库文件版本过高: %d", data.Version)
+	}
+
+	// 版本1的单一题库迁移为默认题库
+	if data.Version < 2 && len(data.Answers) > 0 {
+		data.Banks = []storedBank{newStoredBank(defaultBankName, "", data.Answers)}
+		data.Banks[0].IsDefault = true
+	}
+
+	for i := range data.Banks {
+		data.Banks[i].index = newSearchIndex(data.Banks[i].Answers)
+	}
+
+	s.banks = data.Banks
+	return nil
+}
+
+// newStoredBank 创建一个启用状态的题库
+func newStoredBank(name string, sourceFile string, answers []AnswerItem) storedBank {
+	return storedBank{
+		BankInfo: BankInfo{
+			ID:         uuid.NewString(),
+			Name:       name,
+			SourceFile: sourceFile,
+			ImportedAt: time.Now(),
+			ItemCount:  len(answers),
+			Enabled:    true,
+		},
+		Answers: answers,
+		index:   newSearchIndex(answers),
+	}
+}
+
+// Banks 返回所有题库的信息
+func (s *AnswerStore) Banks() []BankInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]BankInfo, 0, len(s.banks))
+	for _, bank := range s.banks {
+		infos = append(infos, bank.BankInfo)
+	}
+	return infos
+}
+
+// EnabledBanks 返回所有启用的题库及其题目
+func (s *AnswerStore) EnabledBanks() []storedBank {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	banks := []storedBank{}
+	for _, bank := range s.banks {
+		if bank.Enabled {
+			banks = append(banks, bank)
+		}
+	}
+	return banks
+}
+
+// Bank 返回指定题库及其题目
+func (s *AnswerStore) Bank(id string) (storedBank, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, bank := range s.banks {
+		if bank.ID == id {
+			return bank, nil
+		}
+	}
+	return storedBank{}, fmt.Errorf("题库不存在: %s", id)
+}
+
+// Answers 返回所有启用题库中的题目
+func (s *AnswerStore) Answers() []AnswerItem {
+	answers := []AnswerItem{}
+	for _, bank := range s.EnabledBanks() {
+		answers = append(answers, bank.Answers...)
+	}
+	return answers
+}
+
+// AddBank 新建题库并写入磁盘
+func (s *AnswerStore) AddBank(name string, sourceFile string, answers []AnswerItem) (BankInfo, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(sourceFile), filepath.Ext(sourceFile))
+	}
+	if name == "" || name == "." {
+		name = defaultBankName
+	}
+
+	bank := newStoredBank(name, sourceFile, answers)
+
+	err := s.update(func(banks []storedBank) ([]storedBank, error) {
+		return append(banks, bank), nil
+	})
+	if err != nil {
+		return BankInfo{}, err
+	}
+	return bank.BankInfo, nil
+}
+
+// ReplaceDefaultBank 用新的题目替换默认题库，保留其标识、名称和启用状态；默认题库不存在时新建
+// 默认题库按 IsDefault 标记识别，重命名后仍会被替换，同名的普通题库不受影响
+func (s *AnswerStore) ReplaceDefaultBank(answers []AnswerItem) (BankInfo, error) {
+	var info BankInfo
+	err := s.update(func(banks []storedBank) ([]storedBank, error) {
+		for i := range banks {
+			if banks[i].IsDefault {
+				updated := append([]storedBank(nil), banks...)
+				replaced := newStoredBank(banks[i].Name, "", answers)
+				replaced.ID, replaced.Enabled, replaced.IsDefault = banks[i].ID, banks[i].Enabled, true
+				updated[i] = replaced
+				info = replaced.BankInfo
+				return updated, nil
+			}
+		}
+		bank := newStoredBank(defaultBankName, "", answers)
+		bank.IsDefault = true
+		info = bank.BankInfo
+		return append(banks, bank), nil
+	})
+	if err != nil {
+		return BankInfo{}, err
+	}
+	return info, nil
+}
+
+// RenameBank 重命名题库
+func (s *AnswerStore) RenameBank(id string, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("题库名称不能为空")
+	}
+	return s.updateBank(id, func(bank *storedBank) {
+		bank.Name = name
+	})
+}
+
+// SetBankEnabled 启用或停用题库
+func (s *AnswerStore) SetBankEnabled(id string, enabled bool) error {
+	return s.updateBank(id, func(bank *storedBank) {
+		bank.Enabled = enabled
+	})
+}
+
+// DeleteBank 删除题库
+func (s *AnswerStore) DeleteBank(id string) error {
+	return s.update(func(banks []storedBank) ([]storedBank, error) {
+		for i, bank := range banks {
+			if bank.ID == id {
+				return append(banks[:i:i], banks[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("题库不存在: %s", id)
+	})
+}
+
+// updateBank 修改指定题库并写入磁盘
+func (s *AnswerStore) updateBank(id string, modify func(bank *storedBank)) error {
+	return s.update(func(banks []storedBank) ([]storedBank, error) {
+		for i := range banks {
+			if banks[i].ID == id {
+				updated := append([]storedBank(nil), banks...)
+				modify(&updated[i])
+				return updated, nil
+			}
+		}
+		return nil, fmt.Errorf("题库不存在: %s", id)
+	})
+}
+
+// update 基于当前题库计算新的题库列表，写入磁盘成功后才替换内存数据
+func (s *AnswerStore) update(modify func(banks []storedBank) ([]storedBank, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	banks, err := modify(s.banks)
+	if err != nil {
+		return err
+	}
+	if err := s.save(banks); err != nil {
+		return err
+	}
+	s.banks = banks
+	return nil
+}
+
+// save 将题库写入磁盘
+func (s *AnswerStore) save(banks []storedBank) error {
+	content, err := json.MarshalIndent(storeData{
+		Version:   storeVersion,
+		UpdatedAt: time.Now(),
+		Banks:     banks,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码题库数据失败: %v", err)
+	}
+	return writeFileAtomic(s.path, content)
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免写入中断损坏原文件
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建配置目录失败: %v", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入文件失败: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %v", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("保存文件失败: %v", err)
+	}
+
+	return nil
+}
+
+// loadAnswerStore 启动时加载本地题库
+func loadAnswerStore() {
+	if err := answerStore.Load(); err != nil {
+		log.Printf("加载本地题库失败: %v", err)
+		return
+	}
+	log.Printf("已从 %s 加载 %d 个题库", answerStore.Path(), len(answerStore.Banks()))
+}
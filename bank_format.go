@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 题库交换格式
+const (
+	BankFormatJSON  = "json"  // 整个题库为一个JSON文档
+	BankFormatJSONL = "jsonl" // 第一行为文件头，之后每行一道题，便于逐行比较和脚本追加
+	BankFormatYAML  = "yaml"  // 整个题库为一个YAML文档，便于手工编辑
+)
+
+// bankSchema 题库交换文件的格式标识
+const bankSchema = "exam-bank"
+
+// bankSchemaVersion 题库交换文件的格式版本
+// 版本1: schema、version、name 和 answers 字段，题目字段与 AnswerItem 一致
+const bankSchemaVersion = 1
+
+// maxBankLineSize JSONL 文件单行的最大长度
+const maxBankLineSize = 16 * 1024 * 1024
+
+// BankDocument 题库交换文件的内容
+// JSON和YAML格式为整个文档；JSONL格式第一行为不含 answers 的文件头，之后每行一道题
+type BankDocument struct {
+	Schema  string       `json:"schema" yaml:"schema"`                       // 固定为 exam-bank
+	Version int          `json:"version" yaml:"version"`                     // 格式版本
+	Name    string       `json:"name,omitempty" yaml:"name,omitempty"`       // 题库名称
+	Answers []AnswerItem `json:"answers,omitempty" yaml:"answers,omitempty"` // 题目
+
+	rows []int // JSONL 中各题目所在的行号
+}
+
+// bankFormatOf 根据扩展名返回题库交换格式，不是交换格式时返回空字符串
+func bankFormatOf(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json":
+		return BankFormatJSON
+	case ".jsonl", ".ndjson":
+		return BankFormatJSONL
+	case ".yaml", ".yml":
+		return BankFormatYAML
+	}
+	return ""
+}
+
+// newBankDocument 创建当前版本的题库交换文件
+func newBankDocument(name string, answers []AnswerItem) BankDocument {
+	return BankDocument{Schema: bankSchema, Version: bankSchemaVersion, Name: name, Answers: answers}
+}
+
+// checkHeader 校验格式标识和版本，没有格式标识的文档视为不带文件头的题目列表
+func (d BankDocument) checkHeader() error {
+	if d.Schema != "" && d.Schema != bankSchema {
+		return fmt.Errorf("不是题库文件: schema 为 %s", d.Schema)
+	}
+	if d.Version > bankSchemaVersion {
+		return fmt.Errorf("题库文件版本过高: %d，当前支持的最高版本为%d", d.Version, bankSchemaVersion)
+	}
+	return nil
+}
+
+// encodeBank 按格式编码题库交换文件，输出内容稳定，便于在 git 中比较
+func encodeBank(doc BankDocument, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case BankFormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("编码JSON失败: %v", err)
+		}
+	case BankFormatJSONL:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		header := doc
+		header.Answers = nil
+		if err := encoder.Encode(header); err != nil {
+			return nil, fmt.Errorf("编码JSONL失败: %v", err)
+		}
+		for _, answer := range doc.Answers {
+			if err := encoder.Encode(answer); err != nil {
+				return nil, fmt.Errorf("编码JSONL失败: %v", err)
+			}
+		}
+	case BankFormatYAML:
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return nil, fmt.Errorf("编码YAML失败: %v", err)
+		}
+		encoder.Close()
+	default:
+		return nil, fmt.Errorf("不支持的题库格式: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeBank 解析题库交换文件
+// 也接受不带文件头的题目数组，以及 /api/get-global-answers 返回的带 answers 字段的JSON
+func decodeBank(content []byte, format string, report *ImportReport) (BankDocument, error) {
+	var doc BankDocument
+	switch format {
+	case BankFormatJSON:
+		if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(trimmed, &doc.Answers); err != nil {
+				return doc, fmt.Errorf("解析JSON失败: %v", err)
+			}
+		} else if err := json.Unmarshal(content, &doc); err != nil {
+			return doc, fmt.Errorf("解析JSON失败: %v", err)
+		}
+	case BankFormatJSONL:
+		return decodeBankLines(content, report)
+	case BankFormatYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(content, &node); err != nil {
+			return doc, fmt.Errorf("解析YAML失败: %v", err)
+		}
+		var err error
+		if len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+			err = node.Decode(&doc.Answers)
+		} else if len(node.Content) > 0 {
+			err = node.Decode(&doc)
+		}
+		if err != nil {
+			return doc, fmt.Errorf("解析YAML失败: %v", err)
+		}
+	default:
+		return doc, fmt.Errorf("不支持的题库格式: %s", format)
+	}
+	return doc, doc.checkHeader()
+}
+
+// decodeBankLines 逐行解析JSONL题库，第一行带 schema 字段时作为文件头
+// report 不为空时跳过无法解析的行并记录问题，否则遇到无法解析的行时返回错误
+func decodeBankLines(content []byte, report *ImportReport) (BankDocument, error) {
+	doc := BankDocument{Answers: []AnswerItem{}}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, maxBankLineSize)
+
+	row, first := 0, true
+	for scanner.Scan() {
+		row++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if first {
+			first = false
+			var header BankDocument
+			if json.Unmarshal(line, &header) == nil && header.Schema != "" {
+				if err := header.checkHeader(); err != nil {
+					return doc, err
+				}
+				doc.Schema, doc.Version, doc.Name = header.Schema, header.Version, header.Name
+				continue
+			}
+		}
+
+		var answer AnswerItem
+		if err := json.Unmarshal(line, &answer); err != nil {
+			if report == nil {
+				return doc, fmt.Errorf("第%d行解析失败: %v", row, err)
+			}
+			report.TotalRows++
+			report.SkippedRows++
+			report.addIssue(row, IssueParseError, "行格式错误: %v", err)
+			continue
+		}
+		doc.Answers = append(doc.Answers, answer)
+		doc.rows = append(doc.rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return doc, fmt.Errorf("读取JSONL失败: %v", err)
+	}
+	return doc, nil
+}
+
+// parseBankFile 读取JSON、JSONL或YAML题库文件
+// 宽松模式下跳过题目为空、答案超出选项范围或重复的题目并返回诊断报告，报告中的行号在JSONL中为文件行号，其他格式为题目序号
+func (e *ExamService) parseBankFile(filePath string, options ImportOptions) (BankDocument, *ImportReport, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return BankDocument{}, nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	format := bankFormatOf(filePath)
+
+	var report *ImportReport
+	if options.Lenient {
+		report = &ImportReport{Issues: []ImportIssue{}, seen: map[string]int{}}
+	}
+
+	doc, err := decodeBank(content, format, report)
+	if err != nil {
+		return BankDocument{}, nil, err
+	}
+
+	answers := make([]AnswerItem, 0, len(doc.Answers))
+	for i, answer := range doc.Answers {
+		if answer.Options == nil {
+			answer.Options = []string{}
+		}
+		if answer.Answer == nil {
+			answer.Answer = []string{}
+		}
+		row := i + 1
+		if i < len(doc.rows) {
+			row = doc.rows[i]
+		}
+		if report != nil && !report.check(row, nil, 0, answer, true) {
+			continue
+		}
+		answers = append(answers, answer)
+	}
+	doc.Answers = answers
+	return doc, report, nil
+}
+
+// bankForExport 返回要导出的题库名称和题目，id 为空时导出所有启用题库中的题目
+func bankForExport(id string) (string, []AnswerItem, error) {
+	if id == "" {
+		return "全部题库", answerStore.Answers(), nil
+	}
+	bank, err := answerStore.Bank(id)
+	if err != nil {
+		return "", nil, err
+	}
+	return bank.Name, bank.Answers, nil
+}
+
+// ExportBank 将题库导出为JSON、JSONL或YAML格式的文本，id 为空时导出所有启用题库中的题目
+func (e *ExamService) ExportBank(id string, format string) (string, error) {
+	name, answers, err := bankForExport(id)
+	if err != nil {
+		return "", err
+	}
+	content, err := encodeBank(newBankDocument(name, answers), format)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// ExportBankFile 将题库导出到文件，format 为空时按扩展名确定格式
+func (e *ExamService) ExportBankFile(id string, filePath string, format string) error {
+	if format == "" {
+		format = bankFormatOf(filePath)
+	}
+	if format == "" {
+		return fmt.Errorf("无法根据扩展名确定题库格式: %s", filePath)
+	}
+	content, err := e.ExportBank(id, format)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %v", err)
+	}
+	return nil
+}
+
+// bankContentTypes 各题库格式的 Content-Type
+var bankContentTypes = map[string]string{
+	BankFormatJSON:  "application/json; charset=utf-8",
+	BankFormatJSONL: "application/x-ndjson; charset=utf-8",
+	BankFormatYAML:  "application/yaml; charset=utf-8",
+}
+
+// handleExportBank 处理HTTP导出题库请求
+// GET 参数 id 为题库标识（为空时导出所有启用题库中的题目），format 为 json、jsonl 或 yaml，默认 json
+func handleExportBank(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许GET方法
+	if r.Method != "GET" {
+		http.Error(w, "只支持GET方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = BankFormatJSON
+	}
+	contentType, ok := bankContentTypes[format]
+	if !ok {
+		http.Error(w, "不支持的题库格式: "+format, http.StatusBadRequest)
+		return
+	}
+
+	name, answers, err := bankForExport(query.Get("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	content, err := encodeBank(newBankDocument(name, answers), format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + format}))
+	w.Write(content)
+}
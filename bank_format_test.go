@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// roundTripAnswers 覆盖多选答案、空选项以及YAML中容易被误解析为其他类型的字符串
+var roundTripAnswers = []AnswerItem{
+	{
+		Type:        QuestionTypeMultiple,
+		Question:    "以下属于个人防护用品的是",
+		Options:     []string{"安全帽", "安全带", "灭火器", "防护眼镜"},
+		Answer:      []string{"A", "B", "D"},
+		Explanation: "灭火器属于消防器材",
+		Tags:        []string{"第1章", "防护"},
+		Difficulty:  "中等",
+		Source:      "教材第12页",
+	},
+	{
+		Type:     QuestionTypeJudge,
+		Question: "安全帽属于个人防护用品",
+		Options:  []string{},
+		Answer:   []string{"正确"},
+	},
+	{
+		Type:     QuestionTypeSingle,
+		Question: "A: b",
+		Options:  []string{"yes", "1", "null", "~", "- 列表", "#注释"},
+		Answer:   []string{"A"},
+		Extra:    map[string]string{"on": "off", "备注": "3.0"},
+	},
+}
+
+func TestBankFormatRoundTrip(t *testing.T) {
+	for _, format := range []string{BankFormatJSON, BankFormatJSONL, BankFormatYAML} {
+		content, err := encodeBank(newBankDocument("测试题库", roundTripAnswers), format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		doc, err := decodeBank(content, format, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if doc.Schema != bankSchema || doc.Version != bankSchemaVersion || doc.Name != "测试题库" {
+			t.Errorf("%s: 文件头为 %s %d %s", format, doc.Schema, doc.Version, doc.Name)
+		}
+		if !reflect.DeepEqual(doc.Answers, roundTripAnswers) {
+			t.Errorf("%s: 题目为%+v，应为%+v", format, doc.Answers, roundTripAnswers)
+		}
+	}
+}
+
+func TestDecodeBankWithoutHeader(t *testing.T) {
+	tests := []struct {
+		format  string
+		content string
+	}{
+		{BankFormatJSON, `[{"question": "安全帽属于个人防护用品", "options": [], "answer": ["正确"]}]`},
+		{BankFormatYAML, "- question: 安全帽属于个人防护用品\n  options: []\n  answer: [正确]\n"},
+		// 第一行没有 schema 字段时作为题目而不是文件头
+		{BankFormatJSONL, `{"question": "安全帽属于个人防护用品", "options": [], "answer": ["正确"]}`},
+	}
+
+	for _, tt := range tests {
+		doc, err := decodeBank([]byte(tt.content), tt.format, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if doc.Schema != "" || len(doc.Answers) != 1 || doc.Answers[0].Question != "安全帽属于个人防护用品" {
+			t.Errorf("%s: 解析结果为%+v", tt.format, doc)
+		}
+	}
+}
+
+func TestParseBankFileLenientJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.jsonl")
+	content := `{"schema": "exam-bank", "version": 1, "name": "测试题库"}
+{"question": "安全帽属于个人防护用品", "options": [], "answer": ["正确"]}
+{"question": "缺少右括号", "options": [
+{"question": "以下属于安全色的是", "options": ["红色", "粉色"], "answer": ["A"]}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, report, err := (&ExamService{}).parseBankFile(path, ImportOptions{Lenient: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "测试题库" || len(doc.Answers) != 2 {
+		t.Fatalf("题库名称为%q，题目数量为%d，应为测试题库和2", doc.Name, len(doc.Answers))
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Row != 3 || report.Issues[0].Kind != IssueParseError {
+		t.Fatalf("诊断问题为%+v，应为第3行的格式错误", report.Issues)
+	}
+
+	// 严格模式下遇到无法解析的行返回错误
+	if _, _, err := (&ExamService{}).parseBankFile(path, ImportOptions{}); err == nil {
+		t.Fatal("严格模式下应返回错误")
+	}
+}
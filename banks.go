@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ImportBank 将解析出的答案导入为一个新题库
+// name 为空时使用来源文件名
+func (e *ExamService) ImportBank(name string, sourceFile string, answers []AnswerItem) (BankInfo, error) {
+	return answerStore.AddBank(name, sourceFile, answers)
+}
+
+// ListBanks 获取所有题库
+func (e *ExamService) ListBanks() []BankInfo {
+	return answerStore.Banks()
+}
+
+// RenameBank 重命名题库
+func (e *ExamService) RenameBank(id string, name string) error {
+	return answerStore.RenameBank(id, name)
+}
+
+// DeleteBank 删除题库
+func (e *ExamService) DeleteBank(id string) error {
+	return answerStore.DeleteBank(id)
+}
+
+// SetBankEnabled 启用或停用题库，停用的题库不参与搜索
+func (e *ExamService) SetBankEnabled(id string, enabled bool) error {
+	return answerStore.SetBankEnabled(id, enabled)
+}
+
+// SearchBanks 在所有启用的题库中搜索答案
+func (e *ExamService) SearchBanks(query string, filters SearchFilters) ([]SearchResult, error) {
+	results := []SearchResult{}
+
+	for _, bank := range answerStore.EnabledBanks() {
+		var bankResults []SearchResult
+		if filters.Ranking == RankingBM25 && bank.index != nil && strings.TrimSpace(query) != "" {
+			// BM25 使用各题库自己的语料统计
+			bankResults = e.searchBM25(bank.index, bank.Answers, query, filters)
+		} else {
+			// 先通过倒排索引缩小范围，再对候选题目精确打分
+			var err error
+			bankResults, err = e.SearchAnswers(bank.shortlist(query, filters), query, filters)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for i := range bankResults {
+			bankResults[i].BankID = bank.ID
+			bankResults[i].BankName = bank.Name
+		}
+		results = append(results, bankResults...)
+	}
+
+	// 按匹配度排序，同分时保持题库顺序
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results, nil
+}
+
+// ImportBankRequest HTTP导入题库请求结构
+type ImportBankRequest struct {
+	Name       string       `json:"name"`
+	SourceFile string       `json:"sourceFile"`
+	Answers    []AnswerItem `json:"answers"`
+}
+
+// BankRequest HTTP题库操作请求结构
+type BankRequest struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BankResponse HTTP题库操作响应结构
+type BankResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	Bank    *BankInfo  `json:"bank,omitempty"`
+	Banks   []BankInfo `json:"banks,omitempty"`
+}
+
+// writeBankResponse 写入题库操作响应
+func writeBankResponse(w http.ResponseWriter, response BankResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleListBanks 处理HTTP获取题库列表请求
+func handleListBanks(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许GET方法
+	if r.Method != "GET" {
+		http.Error(w, "只支持GET方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	examService := &ExamService{}
+	writeBankResponse(w, BankResponse{
+		Success: true,
+		Banks:   examService.ListBanks(),
+	})
+}
+
+// handleImportBank 处理HTTP导入题库请求
+func handleImportBank(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req ImportBankRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	examService := &ExamService{}
+	bank, err := examService.ImportBank(req.Name, req.SourceFile, req.Answers)
+	if err != nil {
+		writeBankResponse(w, BankResponse{
+			Success: false,
+			Message: "导入题库失败: " + err.Error(),
+		})
+		return
+	}
+
+	writeBankResponse(w, BankResponse{
+		Success: true,
+		Bank:    &bank,
+	})
+}
+
+// handleUpdateBank 处理HTTP题库修改请求，action 为 rename、delete 或 toggle
+func handleUpdateBank(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 设置CORS头
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+		// 处理预检请求
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		// 只允许POST方法
+		if r.Method != "POST" {
+			http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// 解析请求体
+		var req BankRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		examService := &ExamService{}
+
+		var err error
+		switch action {
+		case "rename":
+			err = examService.RenameBank(req.ID, req.Name)
+		case "delete":
+			err = examService.DeleteBank(req.ID)
+		case "toggle":
+			err = examService.SetBankEnabled(req.ID, req.Enabled)
+		}
+		if err != nil {
+			writeBankResponse(w, BankResponse{
+				Success: false,
+				Message: "题库操作失败: " + err.Error(),
+			})
+			return
+		}
+
+		writeBankResponse(w, BankResponse{
+			Success: true,
+			Banks:   examService.ListBanks(),
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// 搜索排序方式
+const (
+	RankingOverlap = "overlap" // 重合度打分（默认）
+	RankingBM25    = "bm25"    // BM25 打分
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2  // 词频饱和度
+	bm25B  = 0.75 // 文档长度归一化程度
+)
+
+// scoredDoc 带分数的题目序号
+type scoredDoc struct {
+	doc   int
+	score float64
+}
+
+// idf 计算词元的逆文档频率，出现在越少题目中的词元权重越高
+func (idx *searchIndex) idf(g gram) float64 {
+	n := float64(len(idx.docLens))
+	df := float64(len(idx.postings[g]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// rankBM25 按BM25对题目打分，返回分数从高到低的前 limit 个题目
+// 分数除以查询中各词元的 idf 之和归一化到0~1，即题目以平均长度恰好包含每个查询词元一次时得1分；
+// keep 不为 nil 时只保留满足条件的题目，accuracy 按归一化后的分数筛选，两者都在截取前 limit 个之前生效
+func (idx *searchIndex) rankBM25(query string, limit int, keep func(doc int) bool, accuracy AccuracyFilters) []scoredDoc {
+	grams := uniqueGrams(indexGrams(query, nil))
+	if len(grams) == 0 || len(idx.docLens) == 0 {
+		return nil
+	}
+
+	avgLen := float64(idx.totalLen) / float64(len(idx.docLens))
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	scores := map[int]float64{}
+	maxScore := 0.0
+	for _, g := range grams {
+		idf := idx.idf(g)
+		maxScore += idf
+		for _, p := range idx.postings[g] {
+			tf := float64(p.freq)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.docLens[p.doc])/avgLen)
+			scores[int(p.doc)] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+	if maxScore == 0 {
+		return nil
+	}
+
+	ranked := make([]scoredDoc, 0, len(scores))
+	for doc, score := range scores {
+		score = math.Min(score/maxScore, 1.0)
+		if (keep != nil && !keep(doc)) || !accuracy.accept(score) {
+			continue
+		}
+		ranked = append(ranked, scoredDoc{doc: doc, score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].doc < ranked[j].doc
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// searchBM25 使用BM25在题库中搜索，语料统计来自 idx
+func (e *ExamService) searchBM25(idx *searchIndex, answers []AnswerItem, query string, filters SearchFilters) []SearchResult {
+	results := []SearchResult{}
+	normalizedQuery := e.normalizeText(query)
+
+	keep := func(doc int) bool { return filters.matchItem(answers[doc]) }
+	for _, ranked := range idx.rankBM25(query, maxCandidates, keep, filters.AccuracyFilters) {
+		answer := answers[ranked.doc]
+
+		// 高亮位置仍按重合度算法计算
+		optionMatches := make(map[string][]int)
+		for _, option := range answer.Options {
+			optionMatches[option] = e.calculateMatchesForOriginalText(option, normalizedQuery)
+		}
+		explanationMatches := []int{}
+		if answer.Explanation != "" {
+			explanationMatches = e.calculateMatchesForOriginalText(answer.Explanation, normalizedQuery)
+		}
+
+		results = append(results, SearchResult{
+			Item:               answer,
+			Score:              ranked.score,
+			Matched:            "BM25: " + normalizedQuery,
+			QuestionMatches:    e.calculateMatchesForOriginalText(answer.Question, normalizedQuery),
+			OptionMatches:      optionMatches,
+			AnswerMatches:      []int{},
+			ExplanationMatches: explanationMatches,
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"time"
+)
+
+// CaptureSearchRequest 一次截图、识别并搜索的请求
+type CaptureSearchRequest struct {
+	Region     string         `json:"region,omitempty"`     // 已保存的截图区域名称
+	Area       *CaptureRegion `json:"area,omitempty"`       // 未指定区域名称时使用的区域
+	Config     OCRConfig      `json:"config"`               // OCR配置
+	Filters    SearchFilters  `json:"filters"`              // 搜索筛选条件
+	HideWindow bool           `json:"hideWindow,omitempty"` // 截图前是否隐藏应用窗口
+}
+
+// StageTimings 各阶段耗时，单位为毫秒
+type StageTimings struct {
+	Screenshot float64 `json:"screenshotMs"` // 截图
+	Crop       float64 `json:"cropMs"`       // 裁剪
+	Preprocess float64 `json:"preprocessMs"` // 图片预处理
+	OCR        float64 `json:"ocrMs"`        // OCR识别
+	Parse      float64 `json:"parseMs"`      // 解析题目结构
+	Search     float64 `json:"searchMs"`     // 搜索题库
+	Total      float64 `json:"totalMs"`      // 总耗时
+}
+
+// CaptureSearchResult 一次截图、识别并搜索的结果
+type CaptureSearchResult struct {
+	Region  CaptureRegion  `json:"region"`  // 使用的截图区域
+	Text    string         `json:"text"`    // 识别出的文字
+	Parsed  ParsedQuestion `json:"parsed"`  // 解析出的题目结构
+	Results []SearchResult `json:"results"` // 按匹配度排序的搜索结果
+	Timings StageTimings   `json:"timings"` // 各阶段耗时
+}
+
+// milliseconds 将耗时换算为毫秒
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// resolveCaptureRegion 确定截图区域，优先使用已保存的区域
+func resolveCaptureRegion(name string, area *CaptureRegion) (CaptureRegion, error) {
+	switch {
+	case name != "":
+		return regionStore.Get(name)
+	case area != nil:
+		return *area, nil
+	default:
+		return CaptureRegion{}, fmt.Errorf("未指定截图区域")
+	}
+}
+
+// CaptureAndSearch 截图并按区域裁剪，OCR识别后解析题目结构并在题库中搜索，返回结果和各阶段耗时
+func (e *ExamService) CaptureAndSearch(request CaptureSearchRequest) (CaptureSearchResult, error) {
+	total := time.Now()
+	result := CaptureSearchResult{}
+
+	// 确定截图区域
+	region, err := resolveCaptureRegion(request.Region, request.Area)
+	if err != nil {
+		return result, err
+	}
+	result.Region = region
+
+	// 截取区域所在的显示器
+	start := time.Now()
+	var screenshot image.Image
+	if request.HideWindow {
+		screenshot, err = e.takeScreenshotWithWindowControl(region.DisplayID, region.DisplayName)
+	} else {
+		screenshot, err = e.captureDisplay(region.DisplayID, region.DisplayName)
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Timings.Screenshot = milliseconds(time.Since(start))
+
+	if err := e.searchScreenshot(&result, screenshot, request.Config, request.Filters); err != nil {
+		return result, err
+	}
+
+	result.Timings.Total = milliseconds(time.Since(total))
+	return result, nil
+}
+
+// searchScreenshot 按 result.Region 裁剪截图，识别、解析并搜索，结果和各阶段耗时写入 result
+func (e *ExamService) searchScreenshot(result *CaptureSearchResult, screenshot image.Image, config OCRConfig, filters SearchFilters) error {
+	// 裁剪、预处理并识别
+	run, err := e.runOCR(result.Region.area(screenshot), config)
+	if err != nil {
+		return err
+	}
+	result.Timings.Crop = milliseconds(run.cropTime)
+	result.Timings.Preprocess = milliseconds(run.preprocessTime)
+	result.Timings.OCR = milliseconds(run.recognizeTime)
+
+	// 解析题目结构
+	start := time.Now()
+	result.Parsed = ParseOCRResults(run.results)
+	result.Parsed.Confidence = run.confidence
+	result.Text = result.Parsed.Text
+	result.Timings.Parse = milliseconds(time.Since(start))
+
+	// 搜索
+	start = time.Now()
+	result.Results, err = e.SearchParsedQuestion(result.Parsed, filters)
+	if err != nil {
+		return err
+	}
+	result.Timings.Search = milliseconds(time.Since(start))
+	return nil
+}
+
+// CaptureSearchResponse HTTP截图搜索响应结构
+type CaptureSearchResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message,omitempty"`
+	Result  *CaptureSearchResult `json:"result,omitempty"`
+}
+
+// handleCaptureSearch 处理HTTP截图、识别并搜索请求
+func handleCaptureSearch(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req CaptureSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	response := CaptureSearchResponse{Success: true}
+	result, err := examService.CaptureAndSearch(req)
+	if err != nil {
+		response = CaptureSearchResponse{
+			Success: false,
+			Message: "截图搜索失败: " + err.Error(),
+		}
+	} else {
+		response.Result = &result
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// regionFileName 截图区域存储文件名
+const regionFileName = "capture_regions.json"
+
+// CaptureRegion 已保存的截图区域，坐标为所在显示器截图中的物理像素
+type CaptureRegion struct {
+	Name          string `json:"name"`                    // 区域名称
+	DisplayID     string `json:"displayId,omitempty"`     // 所在显示器标识，为空时表示主显示器
+	DisplayName   string `json:"displayName,omitempty"`   // 所在显示器名称，显示器标识变化时用于重新匹配
+	DisplayWidth  int    `json:"displayWidth,omitempty"`  // 保存区域时显示器截图的宽度
+	DisplayHeight int    `json:"displayHeight,omitempty"` // 保存区域时显示器截图的高度
+	X             int    `json:"x"`
+	Y             int    `json:"y"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+}
+
+// area 将区域转换为截图区域，screenshot 为所在显示器的截图
+// 显示器分辨率或缩放比例变化导致截图尺寸与保存时不同时，按比例换算区域坐标
+func (r CaptureRegion) area(screenshot image.Image) ScreenshotArea {
+	area := ScreenshotArea{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height, DisplayID: r.DisplayID, screenshot: screenshot}
+	if r.DisplayWidth <= 0 || r.DisplayHeight <= 0 {
+		return area
+	}
+
+	width, height := screenshot.Bounds().Dx(), screenshot.Bounds().Dy()
+	if width == r.DisplayWidth && height == r.DisplayHeight {
+		return area
+	}
+	scaleX := float64(width) / float64(r.DisplayWidth)
+	scaleY := float64(height) / float64(r.DisplayHeight)
+	area.X = int(math.Round(float64(r.X) * scaleX))
+	area.Y = int(math.Round(float64(r.Y) * scaleY))
+	area.Width = int(math.Round(float64(r.Width) * scaleX))
+	area.Height = int(math.Round(float64(r.Height) * scaleY))
+	return area
+}
+
+// RegionStore 截图区域存储
+type RegionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// 全局截图区域存储
+var regionStore = &RegionStore{path: filepath.Join(appConfigDir(), regionFileName)}
+
+// List 返回所有已保存的截图区域
+func (s *RegionStore) List() ([]CaptureRegion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Get 按名称获取截图区域
+func (s *RegionStore) Get(name string) (CaptureRegion, error) {
+	regions, err := s.List()
+	if err != nil {
+		return CaptureRegion{}, err
+	}
+	for _, region := range regions {
+		if region.Name == name {
+			return region, nil
+		}
+	}
+	return CaptureRegion{}, fmt.Errorf("截图区域不存在: %s", name)
+}
+
+// Save 保存截图区域，同名区域会被覆盖
+func (s *RegionStore) Save(region CaptureRegion) error {
+	region.Name = strings.TrimSpace(region.Name)
+	if region.Name == "" {
+		return fmt.Errorf("截图区域名称不能为空")
+	}
+	if region.Width <= 0 || region.Height <= 0 || region.X < 0 || region.Y < 0 {
+		return fmt.Errorf("截图区域无效: %d,%d %dx%d", region.X, region.Y, region.Width, region.Height)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	regions, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range regions {
+		if regions[i].Name == region.Name {
+			regions[i] = region
+			replaced = true
+		}
+	}
+	if !replaced {
+		regions = append(regions, region)
+	}
+
+	return s.save(regions)
+}
+
+// Delete 删除截图区域
+func (s *RegionStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	regions, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, region := range regions {
+		if region.Name == name {
+			return s.save(append(regions[:i], regions[i+1:]...))
+		}
+	}
+	return fmt.Errorf("截图区域不存在: %s", name)
+}
+
+// load 从磁盘读取截图区域，文件不存在时返回空列表
+func (s *RegionStore) load() ([]CaptureRegion, error) {
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []CaptureRegion{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取截图区域失败: %v", err)
+	}
+
+	var regions []CaptureRegion
+	if err := json.Unmarshal(content, &regions); err != nil {
+		return nil, fmt.Errorf("解析截图区域失败: %v", err)
+	}
+	return regions, nil
+}
+
+// save 将截图区域写入磁盘
+func (s *RegionStore) save(regions []CaptureRegion) error {
+	content, err := json.MarshalIndent(regions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码截图区域失败: %v", err)
+	}
+	return writeFileAtomic(s.path, content)
+}
+
+// ListCaptureRegions 获取所有已保存的截图区域
+func (e *ExamService) ListCaptureRegions() ([]CaptureRegion, error) {
+	return regionStore.List()
+}
+
+// SaveCaptureRegion 保存截图区域
+// 未提供显示器名称和尺寸时从当前显示器补全，以便显示器变化后重新匹配和换算
+func (e *ExamService) SaveCaptureRegion(region CaptureRegion) error {
+	if region.DisplayID == VirtualDesktopID {
+		if displays, err := e.ListDisplays(); err == nil && region.DisplayWidth == 0 && region.DisplayHeight == 0 {
+			_, _, region.DisplayWidth, region.DisplayHeight = virtualDesktopBounds(displays)
+		}
+	} else if display, err := e.findDisplay(region.DisplayID, region.DisplayName); err == nil {
+		if region.DisplayName == "" {
+			region.DisplayName = display.Name
+		}
+		if region.DisplayWidth == 0 && region.DisplayHeight == 0 {
+			region.DisplayWidth, region.DisplayHeight = display.Width, display.Height
+		}
+	}
+	return regionStore.Save(region)
+}
+
+// DeleteCaptureRegion 删除截图区域
+func (e *ExamService) DeleteCaptureRegion(name string) error {
+	return regionStore.Delete(name)
+}
+
+// CaptureRegionResponse HTTP截图区域响应结构
+type CaptureRegionResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Regions []CaptureRegion `json:"regions,omitempty"`
+}
+
+// handleCaptureRegions 处理HTTP截图区域请求
+// GET 获取全部区域，POST 保存区域，DELETE 按 name 参数删除区域
+func handleCaptureRegions(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	var err error
+	switch r.Method {
+	case "GET":
+	case "POST":
+		var region CaptureRegion
+		if err := json.NewDecoder(r.Body).Decode(&region); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = examService.SaveCaptureRegion(region)
+	case "DELETE":
+		err = examService.DeleteCaptureRegion(r.URL.Query().Get("name"))
+	default:
+		http.Error(w, "只支持GET、POST和DELETE方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var regions []CaptureRegion
+	if err == nil {
+		regions, err = examService.ListCaptureRegions()
+	}
+
+	response := CaptureRegionResponse{Success: err == nil, Regions: regions}
+	if err != nil {
+		response.Message = "截图区域操作失败: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 标准字段名，即题库文件的默认表头
+const (
+	FieldType        = "类型"
+	FieldQuestion    = "题目"
+	FieldOptions     = "选项"
+	FieldAnswer      = "答案"
+	FieldExplanation = "解析"
+	FieldTags        = "标签"
+	FieldDifficulty  = "难度"
+	FieldSource      = "来源"
+)
+
+// standardFields 所有标准字段，按表头顺序排列
+var standardFields = []string{
+	FieldType, FieldQuestion, FieldOptions, FieldAnswer,
+	FieldExplanation, FieldTags, FieldDifficulty, FieldSource,
+}
+
+// tagSeparators 标签列中可用的分隔符
+var tagSeparators = []string{",", "，", "、", ";", "；", "|", "/"}
+
+// mappingFileName 列映射预设文件名
+const mappingFileName = "column_mappings.json"
+
+// ColumnMapping 列映射配置，将任意表头映射到答案项的标准字段
+type ColumnMapping struct {
+	Name          string              `json:"name"`          // 预设名称
+	Aliases       map[string][]string `json:"aliases"`       // 标准字段 -> 表头别名，标准字段为 类型/题目/选项/答案/解析/标签/难度/来源
+	OptionColumns []string            `json:"optionColumns"` // 每个选项单独一列时的表头，按选项顺序排列；为空时自动识别 A/B/C/D 列
+}
+
+// defaultAliases 内置的表头别名，所有映射都会使用
+var defaultAliases = map[string][]string{
+	FieldType:     {"题型", "题目类型", "试题类型", "type", "question type"},
+	FieldQuestion: {"题干", "问题", "试题", "题目内容", "question", "stem"},
+	FieldOptions:  {"选项内容", "备选项", "options", "choices"},
+	FieldAnswer:   {"正确答案", "参考答案", "标准答案", "answer", "answers", "correct answer"},
+
+	FieldExplanation: {"答案解析", "试题解析", "解释", "说明", "explanation", "analysis"},
+	FieldTags:        {"知识点", "章节", "tags", "tag", "chapter"},
+	FieldDifficulty:  {"难易度", "难易程度", "难度等级", "difficulty", "level"},
+	FieldSource:      {"出处", "题目来源", "source", "reference"},
+}
+
+// optionHeaderPattern 匹配单独选项列的表头，如 A、选项A、A选项、Option A
+var optionHeaderPattern = regexp.MustCompile(`(?i)^(?:选项|option\s*)?([A-H])(?:选项)?$`)
+
+// columnLayout 标题行解析结果
+type columnLayout struct {
+	fields        map[string]int // 标准字段 -> 列位置
+	optionColumns []int          // 单独选项列的位置，按选项顺序排列
+	extra         []string       // 未映射的表头
+	extraIndex    []int          // 未映射表头的列位置
+	header        HeaderError    // 标题行校验结果
+}
+
+// normalizeHeader 统一表头格式，忽略大小写和首尾空白
+func normalizeHeader(header string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+}
+
+// resolveColumns 根据标题行和列映射定位各字段的位置
+// 题目和答案必须存在；选项可以是一列，也可以是每个选项一列；类型可以缺省
+func resolveColumns(headers []string, mapping ColumnMapping) (*columnLayout, error) {
+	layout := &columnLayout{fields: map[string]int{}}
+	used := map[int]bool{}
+
+	// 标准字段：预设别名优先，其次为标准名称和内置别名
+	for _, field := range standardFields {
+		candidates := append(append([]string{}, mapping.Aliases[field]...), field)
+		candidates = append(candidates, defaultAliases[field]...)
+		for _, candidate := range candidates {
+			if idx := findHeader(headers, candidate, used); idx >= 0 {
+				layout.fields[field] = idx
+				used[idx] = true
+				break
+			}
+		}
+	}
+
+	// 单独的选项列
+	if len(mapping.OptionColumns) > 0 {
+		for _, name := range mapping.OptionColumns {
+			if idx := findHeader(headers, name, used); idx >= 0 {
+				layout.optionColumns = append(layout.optionColumns, idx)
+				used[idx] = true
+			}
+		}
+	} else if _, ok := layout.fields[FieldOptions]; !ok {
+		letters := map[int]string{}
+		for i, h := range headers {
+			if m := optionHeaderPattern.FindStringSubmatch(strings.TrimSpace(h)); m != nil && !used[i] {
+				letters[i] = strings.ToUpper(m[1])
+				layout.optionColumns = append(layout.optionColumns, i)
+				used[i] = true
+			}
+		}
+		sort.SliceStable(layout.optionColumns, func(a, b int) bool {
+			return letters[layout.optionColumns[a]] < letters[layout.optionColumns[b]]
+		})
+	}
+
+	// 未映射的列作为额外信息保留
+	for i, h := range headers {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		if !used[i] && h != "" {
+			layout.extra = append(layout.extra, h)
+			layout.extraIndex = append(layout.extraIndex, i)
+		}
+	}
+	layout.header.Extra = layout.extra
+
+	// 检查缺失字段
+	for _, field := range []string{FieldQuestion, FieldAnswer} {
+		if _, ok := layout.fields[field]; !ok {
+			layout.header.Missing = append(layout.header.Missing, field)
+		}
+	}
+	if _, ok := layout.fields[FieldOptions]; !ok && len(layout.optionColumns) == 0 {
+		layout.header.Missing = append(layout.header.Missing, FieldOptions)
+	}
+	if len(layout.header.Missing) > 0 {
+		return nil, layout.header
+	}
+
+	return layout, nil
+}
+
+// findHeader 查找尚未使用的表头位置，找不到时返回-1
+func findHeader(headers []string, name string, used map[int]bool) int {
+	name = normalizeHeader(name)
+	for i, h := range headers {
+		if !used[i] && normalizeHeader(h) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// cell 返回指定字段所在单元格的内容
+func (l *columnLayout) cell(record []string, field string) string {
+	idx, ok := l.fields[field]
+	if ok && idx < len(record) {
+		return record[idx]
+	}
+	return ""
+}
+
+// buildAnswerItem 将一行数据转换为答案项
+func (e *ExamService) buildAnswerItem(record []string, layout *columnLayout, optionSeparator string, answerSeparator string) AnswerItem {
+	answer := AnswerItem{
+		Type:     strings.TrimSpace(layout.cell(record, FieldType)),
+		Question: strings.TrimSpace(layout.cell(record, FieldQuestion)),
+		Options:  []string{},
+		Answer:   []string{},
+	}
+
+	// 拆分选项，每个选项单独一列时忽略空列
+	if len(layout.optionColumns) > 0 {
+		for _, idx := range layout.optionColumns {
+			if idx < len(record) && strings.TrimSpace(record[idx]) != "" {
+				answer.Options = append(answer.Options, strings.TrimSpace(record[idx]))
+			}
+		}
+	} else {
+		optionsStr := layout.cell(record, FieldOptions)
+		if optionSeparator != "" {
+			separator := e.parseSeparator(optionSeparator)
+			answer.Options = strings.Split(optionsStr, separator)
+		} else {
+			answer.Options = []string{optionsStr}
+		}
+	}
+
+	// 拆分答案
+	answerStr := layout.cell(record, FieldAnswer)
+	if answerStr != "" {
+		separator := e.parseSeparator(answerSeparator)
+		answer.Answer = strings.Split(answerStr, separator)
+	}
+
+	// 解析、标签、难度和来源
+	answer.Explanation = strings.TrimSpace(layout.cell(record, FieldExplanation))
+	answer.Tags = splitTags(layout.cell(record, FieldTags))
+	answer.Difficulty = strings.TrimSpace(layout.cell(record, FieldDifficulty))
+	answer.Source = strings.TrimSpace(layout.cell(record, FieldSource))
+
+	// 保留未映射的列
+	for i, idx := range layout.extraIndex {
+		if idx < len(record) && strings.TrimSpace(record[idx]) != "" {
+			if answer.Extra == nil {
+				answer.Extra = map[string]string{}
+			}
+			answer.Extra[layout.extra[i]] = strings.TrimSpace(record[idx])
+		}
+	}
+
+	return answer
+}
+
+// splitTags 拆分标签列，去掉空白和重复的标签
+func splitTags(value string) []string {
+	for _, sep := range tagSeparators[1:] {
+		value = strings.ReplaceAll(value, sep, tagSeparators[0])
+	}
+
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(value, tagSeparators[0]) {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// MappingStore 列映射预设存储
+type MappingStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// 全局列映射预设存储
+var mappingStore = &MappingStore{path: filepath.Join(appConfigDir(), mappingFileName)}
+
+// List 返回所有已保存的列映射预设
+func (s *MappingStore) List() ([]ColumnMapping, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Get 按名称获取列映射预设，名称为空时返回默认映射
+func (s *MappingStore) Get(name string) (ColumnMapping, error) {
+	if name == "" {
+		return ColumnMapping{}, nil
+	}
+
+	mappings, err := s.List()
+	if err != nil {
+		return ColumnMapping{}, err
+	}
+	for _, mapping := range mappings {
+		if mapping.Name == name {
+			return mapping, nil
+		}
+	}
+	return ColumnMapping{}, fmt.Errorf("列映射预设不存在: %s", name)
+}
+
+// Save 保存列映射预设，同名预设会被覆盖
+func (s *MappingStore) Save(mapping ColumnMapping) error {
+	mapping.Name = strings.TrimSpace(mapping.Name)
+	if mapping.Name == "" {
+		return fmt.Errorf("列映射预设名称不能为空")
+	}
+	for field := range mapping.Aliases {
+		if indexOfField(field) < 0 {
+			return fmt.Errorf("未知的标准字段: %s", field)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mappings, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i := range mappings {
+		if mappings[i].Name == mapping.Name {
+			mappings[i] = mapping
+			replaced = true
+		}
+	}
+	if !replaced {
+		mappings = append(mappings, mapping)
+	}
+
+	return s.save(mappings)
+}
+
+// Delete 删除列映射预设
+func (s *MappingStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mappings, err := s.load()
+	if err != nil {
+		return err
+	}
+	for i, mapping := range mappings {
+		if mapping.Name == name {
+			return s.save(append(mappings[:i], mappings[i+1:]...))
+		}
+	}
+	return fmt.Errorf("列映射预设不存在: %s", name)
+}
+
+// load 从磁盘读取预设，文件不存在时返回空列表
+func (s *MappingStore) load() ([]ColumnMapping, error) {
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return []ColumnMapping{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取列映射预设失败: %v", err)
+	}
+
+	var mappings []ColumnMapping
+	if err := json.Unmarshal(content, &mappings); err != nil {
+		return nil, fmt.Errorf("解析列映射预设失败: %v", err)
+	}
+	return mappings, nil
+}
+
+// save 将预设写入磁盘
+func (s *MappingStore) save(mappings []ColumnMapping) error {
+	content, err := json.MarshalIndent(mappings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("编码列映射预设失败: %v", err)
+	}
+	return writeFileAtomic(s.path, content)
+}
+
+// indexOfField 返回标准字段的序号，不是标准字段时返回-1
+func indexOfField(field string) int {
+	for i, f := range standardFields {
+		if f == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// ListColumnMappings 获取所有列映射预设
+func (e *ExamService) ListColumnMappings() ([]ColumnMapping, error) {
+	return mappingStore.List()
+}
+
+// SaveColumnMapping 保存列映射预设
+func (e *ExamService) SaveColumnMapping(mapping ColumnMapping) error {
+	return mappingStore.Save(mapping)
+}
+
+// DeleteColumnMapping 删除列映射预设
+func (e *ExamService) DeleteColumnMapping(name string) error {
+	return mappingStore.Delete(name)
+}
+
+// ColumnMappingResponse HTTP列映射预设响应结构
+type ColumnMappingResponse struct {
+	Success  bool            `json:"success"`
+	Message  string          `json:"message,omitempty"`
+	Mappings []ColumnMapping `json:"mappings,omitempty"`
+}
+
+// handleColumnMappings 处理HTTP列映射预设请求
+// GET 获取全部预设，POST 保存预设，DELETE 按 name 参数删除预设
+func handleColumnMappings(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	var err error
+	switch r.Method {
+	case "GET":
+	case "POST":
+		var mapping ColumnMapping
+		if err := json.NewDecoder(r.Body).Decode(&mapping); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = examService.SaveColumnMapping(mapping)
+	case "DELETE":
+		err = examService.DeleteColumnMapping(r.URL.Query().Get("name"))
+	default:
+		http.Error(w, "只支持GET、POST和DELETE方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var mappings []ColumnMapping
+	if err == nil {
+		mappings, err = examService.ListColumnMappings()
+	}
+
+	response := ColumnMappingResponse{Success: err == nil, Mappings: mappings}
+	if err != nil {
+		response.Message = "列映射预设操作失败: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// confusablesFileName 用户自定义易混淆字符文件名
+const confusablesFileName = "confusables.txt"
+
+// defaultConfusableCost 易混淆字符互相替换的编辑代价，普通替换代价为1
+const defaultConfusableCost = 0.3
+
+// defaultConfusableGroups 内置的OCR易混淆字符组，同组字符互相替换只计部分编辑代价
+// 文本在比较前已转为小写并完成全角半角、繁简转换，因此只需列出小写半角简体形式
+var defaultConfusableGroups = []string{
+	"己已巳", "未末", "土士", "日曰", "人入八", "大太犬", "天夭", "千干于",
+	"王玉主", "刀力", "贝见", "午牛", "免兔", "候侯", "折拆", "即既",
+	"拔拨", "账帐", "辨辩辫", "戍戌戊", "析折", "历厉", "径经", "治冶",
+	"侍待", "徒徙", "栗粟", "壁璧", "汩汨", "崇祟", "姆拇", "酒洒",
+	"0od", "1li", "5s", "8b", "2z", "9gq", "6b", "uv", "一-",
+}
+
+// runePair 一对字符，较小的字符在前
+type runePair struct {
+	a, b rune
+}
+
+// newRunePair 创建无序字符对
+func newRunePair(a, b rune) runePair {
+	if a > b {
+		a, b = b, a
+	}
+	return runePair{a: a, b: b}
+}
+
+// confusableCosts 易混淆字符对的替换代价
+type confusableCosts map[runePair]float64
+
+// cost 返回两个字符互相替换的代价，低置信度通配符与任意字符替换都不计代价
+func (c confusableCosts) cost(a, b rune) float64 {
+	if a == b || a == wildcardRune || b == wildcardRune {
+		return 0
+	}
+	if cost, ok := c[newRunePair(a, b)]; ok {
+		return cost
+	}
+	return 1
+}
+
+// confusableTable 易混淆字符表
+type confusableTable struct {
+	mu    sync.RWMutex
+	costs confusableCosts
+}
+
+// 全局易混淆字符表
+var confusables = newConfusableTable(defaultConfusableGroups)
+
+// newConfusableTable 由字符组创建易混淆字符表，组后可以空白分隔代价
+func newConfusableTable(groups []string) *confusableTable {
+	t := &confusableTable{}
+	t.set(groups)
+	return t
+}
+
+// set 替换全部字符组
+func (t *confusableTable) set(groups []string) {
+	costs := confusableCosts{}
+	for _, group := range groups {
+		fields := strings.Fields(group)
+		if len(fields) == 0 {
+			continue
+		}
+
+		cost := defaultConfusableCost
+		if len(fields) > 1 {
+			if c, err := strconv.ParseFloat(fields[1], 64); err == nil && c >= 0 && c <= 1 {
+				cost = c
+			}
+		}
+
+		chars := []rune(strings.ToLower(fields[0]))
+		for i := range chars {
+			for j := i + 1; j < len(chars); j++ {
+				if chars[i] != chars[j] {
+					costs[newRunePair(chars[i], chars[j])] = cost
+				}
+			}
+		}
+	}
+
+	t.mu.Lock()
+	t.costs = costs
+	t.mu.Unlock()
+}
+
+// current 返回当前的替换代价表，返回的表不会再被修改
+func (t *confusableTable) current() confusableCosts {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.costs
+}
+
+// confusablesPath 返回用户自定义易混淆字符文件路径
+func confusablesPath() string {
+	return filepath.Join(appConfigDir(), confusablesFileName)
+}
+
+// readConfusableGroups 读取用户自定义的字符组，文件不存在时返回空列表
+func readConfusableGroups() ([]string, error) {
+	content, err := os.ReadFile(confusablesPath())
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取易混淆字符失败: %v", err)
+	}
+
+	groups := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			groups = append(groups, line)
+		}
+	}
+	return groups, nil
+}
+
+// loadConfusables 加载内置和用户自定义的易混淆字符
+func loadConfusables() {
+	groups, err := readConfusableGroups()
+	if err != nil {
+		log.Printf("加载易混淆字符失败: %v", err)
+		return
+	}
+	confusables.set(append(append([]string{}, defaultConfusableGroups...), groups...))
+}
+
+// GetConfusables 获取用户自定义的易混淆字符组
+func (e *ExamService) GetConfusables() ([]string, error) {
+	return readConfusableGroups()
+}
+
+// SetConfusables 保存用户自定义的易混淆字符组并立即生效
+// 每组为若干互相易混淆的字符，可在其后以空格分隔替换代价（0~1）
+func (e *ExamService) SetConfusables(groups []string) error {
+	lines := []string{}
+	for _, group := range groups {
+		if group = strings.TrimSpace(group); group != "" {
+			lines = append(lines, group)
+		}
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+	if err := writeFileAtomic(confusablesPath(), []byte(content)); err != nil {
+		return err
+	}
+	confusables.set(append(append([]string{}, defaultConfusableGroups...), lines...))
+	return nil
+}
+
+// ConfusablesRequest HTTP易混淆字符请求结构
+type ConfusablesRequest struct {
+	Groups []string `json:"groups"`
+}
+
+// ConfusablesResponse HTTP易混淆字符响应结构
+type ConfusablesResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+}
+
+// handleConfusables 处理HTTP易混淆字符请求
+// GET 获取用户自定义字符组，POST 保存用户自定义字符组
+func handleConfusables(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	var err error
+	switch r.Method {
+	case "GET":
+	case "POST":
+		var req ConfusablesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = examService.SetConfusables(req.Groups)
+	default:
+		http.Error(w, "只支持GET和POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var groups []string
+	if err == nil {
+		groups, err = examService.GetConfusables()
+	}
+
+	response := ConfusablesResponse{Success: err == nil, Groups: groups}
+	if err != nil {
+		response.Message = "易混淆字符操作失败: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
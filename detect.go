@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// autoSetting 表示由程序自动检测的编码或分隔符
+const autoSetting = "auto"
+
+// DetectedSettings 自动检测出的文件导入设置
+type DetectedSettings struct {
+	Encoding        string `json:"encoding"`        // 文件编码
+	HasBOM          bool   `json:"hasBom"`          // 是否带有BOM
+	Delimiter       string `json:"delimiter"`       // CSV字段分隔符
+	OptionSeparator string `json:"optionSeparator"` // 建议的选项分隔符，转义形式如 \n
+	AnswerSeparator string `json:"answerSeparator"` // 建议的答案分隔符，转义形式如 \n
+}
+
+// commonSimplified 常用简体汉字，用于判断GBK/GB18030解码结果是否合理
+const commonSimplified = "的一是不了人我在有他这中大来上个国到说们为子和你地出道也时年得就那要下以生会自着去之过家学对可她里后小么心多天而能好都然没日于起还发成事只作当想看文无开手十用主行方又如前所本见经头面公同三已老从动两长知民样现分将外但身些与高意进把法此实回二理美点月明其种声全工己话儿者向情部正名定女问力机给等几很业最间新什打便位因重被走电四第门相次东政海口使教西再平真听世气信北少关并内加化由却代军产入先山五太水万市眼体别处总才场师书比住员九笑性通目华报立马命张活难神数件安表原车白应路期叫死常提感金何更反合放做系计或司利受光王果亲界及今京务制解各任至清物台象记边共风战干接它许八特觉望直服毛林题建南度统色字请交爱让认算论百吃义科怎元社术结六功指思非流每青管夫连远资队跟带花快条院变联言权往展该领传近留红治决周保达办运武半候七必城父强步完革深区即求品士转量空甚众技轻程告江语英基派满式李息写呢识极令黄德收脸钱党倒未持取设始版双历越史商千片容研像找友孩站广改议形委早房音火际则首单据导影失拿网香似斯专石若兵弟谁校读志飞观争究包组造落视济喜离虽坏兴切引器示亚选答案项错误"
+
+// commonTraditional 常用繁体汉字，用于判断Big5解码结果是否合理
+const commonTraditional = "的一是不了人我在有他這中大來上個國到說們為子和你地出道也時年得就那要下以生會自著去之過家學對可她裡後小麼心多天而能好都然沒日於起還發成事只作當想看文無開手十用主行方又如前所本見經頭面公同三已老從動兩長知民樣現分將外但身些與高意進把法此實回二理美點月明其種聲全工己話兒者向情部正名定女問力機給等幾很業最間新什打便位因重被走電四第門相次東政海口使教西再平真聽世氣信北少關並內加化由卻代軍產入先山五太水萬市眼體別處總才場師書比住員九笑性通目華報立馬命張活難神數件安表原車白應路期叫死常提感金何更反合放做系計或司利受光王果親界及今京務制解各任至清物臺象記邊共風戰乾接它許八特覺望直服毛林題建南度統色字請交愛讓認算論百吃義科怎元社術結六功指思非流每青管夫連遠資隊跟帶花快條院變聯言權往展該領傳近留紅治決周保達辦運武半候七必城父強步完革深區即求品士轉量空甚眾技輕程告江語英基派滿式李息寫呢識極令黃德收臉錢黨倒未持取設始版雙歷越史商千片容研像找友孩站廣改議形委早房音火際則首單據導影失拿網香似斯專石若兵弟誰校讀志飛觀爭究包組造落視濟喜離雖壞興切引器示亞選答案項錯誤"
+
+// getEncoding 根据编码名称获取对应的编码器
+func getEncoding(encodingName string) (encoding.Encoding, error) {
+	switch strings.ToLower(encodingName) {
+	case "utf8", "utf-8":
+		return nil, nil // UTF-8是默认编码
+	case "gbk", "gb2312":
+		return simplifiedchinese.GBK, nil
+	case "gb18030":
+		return simplifiedchinese.GB18030, nil
+	case "big5":
+		return traditionalchinese.Big5, nil
+	case "utf-16le":
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), nil
+	case "utf-16be":
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), nil
+	default:
+		return nil, fmt.Errorf("不支持的编码格式: %s，仅支持UTF-8、UTF-16、GBK、GB18030和Big5", encodingName)
+	}
+}
+
+// detectEncoding 通过BOM和内容推测文件编码，返回编码名称和BOM长度
+func detectEncoding(data []byte) (string, int) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8", 3
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return "utf-16le", 2
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return "utf-16be", 2
+	}
+
+	// 只取前64KB作为样本，截断处可能落在多字节字符中间
+	sample := data
+	if len(sample) > 64*1024 {
+		sample = sample[:64*1024]
+		for i := 0; i < utf8.UTFMax && !utf8.Valid(sample); i++ {
+			sample = sample[:len(sample)-1]
+		}
+	}
+	if utf8.Valid(sample) {
+		return "utf-8", 0
+	}
+
+	// 分别按GB18030和Big5解码，常用字占比更高者胜出
+	gbScore := encodingScore(sample, simplifiedchinese.GB18030, commonSimplified)
+	big5Score := encodingScore(sample, traditionalchinese.Big5, commonTraditional)
+	if big5Score > gbScore {
+		return "big5", 0
+	}
+	return "gb18030", 0
+}
+
+// encodingScore 计算按指定编码解码后常用字所占比例，解码失败的字符会扣分
+func encodingScore(sample []byte, enc encoding.Encoding, common string) float64 {
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), sample)
+	if err != nil && len(decoded) == 0 {
+		return -1
+	}
+
+	total, hits := 0, 0
+	for _, r := range string(decoded) {
+		if r < 0x80 {
+			continue
+		}
+		total++
+		switch {
+		case r == utf8.RuneError:
+			hits -= 2
+		case strings.ContainsRune(common, r):
+			hits++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(hits) / float64(total)
+}
+
+// decodeBytes 按指定编码将数据解码为字符串，encodingName 为 auto 时自动检测
+// 返回解码后的文本和实际使用的编码信息
+func decodeBytes(data []byte, encodingName string) (string, DetectedSettings, error) {
+	settings := DetectedSettings{}
+
+	name, bomLen := detectEncoding(data)
+	if strings.ToLower(encodingName) != autoSetting {
+		name = strings.ToLower(encodingName)
+		// 指定编码时仍然去掉对应的BOM
+		bomLen = 0
+		if (name == "utf8" || name == "utf-8") && bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}) {
+			bomLen = 3
+		}
+	}
+	settings.Encoding = name
+	settings.HasBOM = bomLen > 0
+	data = data[bomLen:]
+
+	enc, err := getEncoding(name)
+	if err != nil {
+		return "", settings, err
+	}
+	if enc == nil {
+		return string(data), settings, nil
+	}
+
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), data)
+	if err != nil {
+		return "", settings, fmt.Errorf("文件解码失败: %v", err)
+	}
+	return string(decoded), settings, nil
+}
+
+// decodeFile 读取文件并解码为字符串
+func decodeFile(filePath string, encodingName string) (string, DetectedSettings, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", DetectedSettings{}, fmt.Errorf("无法打开文件: %v", err)
+	}
+	return decodeBytes(data, encodingName)
+}
+
+// csvDelimiters 支持自动检测的CSV字段分隔符，按优先级排列
+var csvDelimiters = []rune{',', '\t', ';'}
+
+// detectDelimiter 检测CSV字段分隔符
+// 优先选择能解析出全部必需列的分隔符，否则选择各行列数最一致的分隔符
+func detectDelimiter(text string) rune {
+	best, bestScore := csvDelimiters[0], -1
+	for _, delimiter := range csvDelimiters {
+		records := sampleRecords(text, delimiter, 20)
+		if len(records) == 0 {
+			continue
+		}
+
+		header := records[0]
+		score := 0
+		if len(header) > 1 {
+			for _, record := range records {
+				if len(record) == len(header) {
+					score++
+				}
+			}
+		}
+		if _, err := resolveColumns(header, ColumnMapping{}); err == nil {
+			score += 1000
+		}
+
+		if score > bestScore {
+			best, bestScore = delimiter, score
+		}
+	}
+	return best
+}
+
+// sampleRecords 使用指定分隔符读取前 limit 条记录
+func sampleRecords(text string, delimiter rune, limit int) [][]string {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	records := [][]string{}
+	for len(records) < limit {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// optionSeparatorCandidates 候选选项分隔符（转义形式），按优先级排列
+var optionSeparatorCandidates = []string{"\\n", "|", "；", ";", "#", "、", "，", ",", "\\t"}
+
+// answerSeparatorCandidates 候选答案分隔符（转义形式），按优先级排列
+var answerSeparatorCandidates = []string{",", "，", "|", ";", "；", "、", "\\n", "\\s"}
+
+// proposeSeparators 根据样本数据推测选项和答案分隔符
+func (e *ExamService) proposeSeparators(records [][]string, layout *columnLayout) (string, string) {
+	column := func(field string) []string {
+		values := []string{}
+		for _, record := range records {
+			if value := strings.TrimSpace(layout.cell(record, field)); value != "" {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	// 选项：拆分后为2~10项的样本越多越好，各项以 A/B/C 依次开头时额外加分
+	optionSeparator, bestScore := "\\n", 0
+	for _, candidate := range optionSeparatorCandidates {
+		sep := e.parseSeparator(candidate)
+		score := 0
+		for _, value := range column(FieldOptions) {
+			parts := strings.Split(value, sep)
+			if len(parts) < 2 || len(parts) > 10 {
+				continue
+			}
+			score += 2
+			if hasSequentialLabels(parts) {
+				score += 3
+			}
+		}
+		if score > bestScore {
+			optionSeparator, bestScore = candidate, score
+		}
+	}
+
+	// 答案：选择出现次数最多的分隔符；多选答案写作 ABC 时按字符拆分
+	answerSeparator, bestCount := ",", 0
+	for _, candidate := range answerSeparatorCandidates {
+		sep := e.parseSeparator(candidate)
+		count := 0
+		for _, value := range column(FieldAnswer) {
+			if strings.Contains(value, sep) {
+				count++
+			}
+		}
+		if count > bestCount {
+			answerSeparator, bestCount = candidate, count
+		}
+	}
+	if bestCount == 0 && lettersOnly(column(FieldAnswer)) {
+		answerSeparator = ""
+	}
+
+	return optionSeparator, answerSeparator
+}
+
+// hasSequentialLabels 判断各项是否依次以 A、B、C... 开头
+func hasSequentialLabels(parts []string) bool {
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" || rune(part[0]) != rune('A'+i) && rune(part[0]) != rune('a'+i) {
+			return false
+		}
+	}
+	return true
+}
+
+// lettersOnly 判断答案是否都由字母组成，且至少有一个多字母答案
+func lettersOnly(values []string) bool {
+	multi := false
+	for _, value := range values {
+		for _, c := range value {
+			if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+				return false
+			}
+		}
+		if len(value) > 1 {
+			multi = true
+		}
+	}
+	return multi
+}
+
+// DetectFileSettings 自动检测文件的编码、CSV分隔符以及选项和答案分隔符
+func (e *ExamService) DetectFileSettings(filePath string) (DetectedSettings, error) {
+	text, settings, err := decodeFile(filePath, autoSetting)
+	if err != nil {
+		return settings, err
+	}
+
+	delimiter := detectDelimiter(text)
+	settings.Delimiter = string(delimiter)
+
+	records := sampleRecords(text, delimiter, 50)
+	if len(records) > 0 {
+		layout, err := resolveColumns(records[0], ColumnMapping{})
+		if err == nil {
+			settings.OptionSeparator, settings.AnswerSeparator = e.proposeSeparators(records[1:], layout)
+		}
+	}
+
+	return settings, nil
+}
+
+// ParseCSVFileAuto 自动检测编码和分隔符并解析CSV文件
+func (e *ExamService) ParseCSVFileAuto(filePath string) (ImportResult, error) {
+	answers, _, settings, err := e.parseCSV(filePath, ImportOptions{
+		Encoding:        autoSetting,
+		OptionSeparator: autoSetting,
+		AnswerSeparator: autoSetting,
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+	return ImportResult{Answers: answers, Settings: &settings}, nil
+}
+
+// DetectSettingsRequest HTTP检测导入设置请求结构
+type DetectSettingsRequest struct {
+	FilePath string `json:"filePath"`
+}
+
+// DetectSettingsResponse HTTP检测导入设置响应结构
+type DetectSettingsResponse struct {
+	Success  bool              `json:"success"`
+	Message  string            `json:"message,omitempty"`
+	Settings *DetectedSettings `json:"settings,omitempty"`
+}
+
+// handleDetectSettings 处理HTTP检测导入设置请求
+func handleDetectSettings(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req DetectSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	settings, err := examService.DetectFileSettings(req.FilePath)
+	if err != nil {
+		response := DetectSettingsResponse{
+			Success: false,
+			Message: "检测导入设置失败: " + err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := DetectSettingsResponse{
+		Success:  true,
+		Settings: &settings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
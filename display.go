@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// VirtualDesktopID 表示由所有显示器拼成的虚拟桌面
+const VirtualDesktopID = "virtual"
+
+// DisplayInfo 显示器信息，位置和尺寸为物理像素
+type DisplayInfo struct {
+	ID          string  `json:"id"`          // 显示器标识
+	Name        string  `json:"name"`        // 显示器名称，Windows 下为设备名，如 \\.\DISPLAY1
+	X           int     `json:"x"`           // 在虚拟桌面中的位置
+	Y           int     `json:"y"`           //
+	Width       int     `json:"width"`       // 宽度
+	Height      int     `json:"height"`      // 高度
+	ScaleFactor float64 `json:"scaleFactor"` // 缩放比例（DPI/96）
+	IsPrimary   bool    `json:"isPrimary"`   // 是否为主显示器
+	index       int     // 在显示器列表中的序号，用于 macOS 的 screencapture -D
+}
+
+// ListDisplays 获取所有显示器
+func (e *ExamService) ListDisplays() ([]DisplayInfo, error) {
+	app := application.Get()
+	if app == nil {
+		return nil, fmt.Errorf("无法获取应用实例")
+	}
+
+	displays := []DisplayInfo{}
+	for i, screen := range app.Screen.GetAll() {
+		bounds := screen.PhysicalBounds
+		if bounds.Width == 0 || bounds.Height == 0 {
+			// 未提供物理尺寸时由逻辑尺寸和缩放比例换算
+			scale := float64(screen.ScaleFactor)
+			if scale <= 0 {
+				scale = 1
+			}
+			bounds = application.Rect{
+				X:      int(math.Round(float64(screen.Bounds.X) * scale)),
+				Y:      int(math.Round(float64(screen.Bounds.Y) * scale)),
+				Width:  int(math.Round(float64(screen.Bounds.Width) * scale)),
+				Height: int(math.Round(float64(screen.Bounds.Height) * scale)),
+			}
+		}
+
+		displays = append(displays, DisplayInfo{
+			ID:          screen.ID,
+			Name:        screen.Name,
+			X:           bounds.X,
+			Y:           bounds.Y,
+			Width:       bounds.Width,
+			Height:      bounds.Height,
+			ScaleFactor: float64(screen.ScaleFactor),
+			IsPrimary:   screen.IsPrimary,
+			index:       i + 1,
+		})
+	}
+	return displays, nil
+}
+
+// findDisplay 按标识查找显示器，标识为空时返回主显示器
+// 标识变化（如重新插拔后）时按名称匹配唯一的显示器
+func (e *ExamService) findDisplay(id, name string) (DisplayInfo, error) {
+	displays, err := e.ListDisplays()
+	if err != nil {
+		return DisplayInfo{}, err
+	}
+
+	for _, display := range displays {
+		if (id == "" && display.IsPrimary) || (id != "" && display.ID == id) {
+			return display, nil
+		}
+	}
+
+	var matched []DisplayInfo
+	for _, display := range displays {
+		if name != "" && display.Name == name {
+			matched = append(matched, display)
+		}
+	}
+	if len(matched) == 1 {
+		return matched[0], nil
+	}
+	return DisplayInfo{}, fmt.Errorf("显示器不存在: %s", id)
+}
+
+// virtualDesktopBounds 返回所有显示器拼成的虚拟桌面范围
+func virtualDesktopBounds(displays []DisplayInfo) (int, int, int, int) {
+	if len(displays) == 0 {
+		return 0, 0, 0, 0
+	}
+	minX, minY := displays[0].X, displays[0].Y
+	maxX, maxY := displays[0].X+displays[0].Width, displays[0].Y+displays[0].Height
+	for _, d := range displays[1:] {
+		minX, minY = min(minX, d.X), min(minY, d.Y)
+		maxX, maxY = max(maxX, d.X+d.Width), max(maxY, d.Y+d.Height)
+	}
+	return minX, minY, maxX - minX, maxY - minY
+}
+
+// TakeDisplayScreenshot 截取指定显示器，返回PNG格式的 data URL
+// displayID 为空时截取主显示器，为 VirtualDesktopID 时截取整个虚拟桌面
+func (e *ExamService) TakeDisplayScreenshot(displayID string) (string, error) {
+	img, err := e.captureDisplay(displayID, "")
+	if err != nil {
+		return "", err
+	}
+	return imageDataURL(img)
+}
+
+// captureDisplay 截取显示器，标识找不到时按 displayName 匹配
+// 设置了截图文件时直接读取该文件
+func (e *ExamService) captureDisplay(displayID, displayName string) (image.Image, error) {
+	screenshotMu.Lock()
+	file := screenshotFile
+	screenshotMu.Unlock()
+	if file != "" {
+		return readScreenshotFile(file)
+	}
+
+	// Linux 下依次尝试各截图后端
+	if runtime.GOOS == "linux" {
+		img, err := e.takeLinuxScreenshot(displayID, displayName)
+		if err != nil {
+			return nil, fmt.Errorf("截图失败: %v", err)
+		}
+		return img, nil
+	}
+
+	// 每次截图使用独立的临时文件，避免并发截图互相覆盖
+	temp, err := os.CreateTemp("", "screenshot-*.png")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	tempFile := temp.Name()
+	temp.Close()
+	defer os.Remove(tempFile)
+
+	cmd, err := e.screenshotCommand(displayID, displayName, tempFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("截图失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// 读取截图文件
+	imageData, err := os.ReadFile(tempFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取截图文件失败: %v", err)
+	}
+	return decodeImage(imageData)
+}
+
+// windowsCaptureScript 生成 Windows 下截图的 PowerShell 脚本，boundsExpr 为截图范围表达式
+// 脚本先声明DPI感知，使不同缩放比例的显示器都按物理像素截图
+func windowsCaptureScript(boundsExpr, file string) string {
+	return "Add-Type -AssemblyName System.Windows.Forms; Add-Type -AssemblyName System.Drawing; " +
+		"Add-Type -Name DPI -Namespace Native -MemberDefinition '[DllImport(\"user32.dll\")] public static extern bool SetProcessDPIAware();'; " +
+		"[Native.DPI]::SetProcessDPIAware() | Out-Null; " +
+		"$bounds = " + boundsExpr + "; if (-not $bounds) { throw 'display not found' }; " +
+		"$bitmap = New-Object System.Drawing.Bitmap $bounds.Width, $bounds.Height; $graphics = [System.Drawing.Graphics]::FromImage($bitmap); " +
+		"$graphics.CopyFromScreen($bounds.X, $bounds.Y, 0, 0, $bitmap.Size); " +
+		"$bitmap.Save('" + file + "', [System.Drawing.Imaging.ImageFormat]::Png); $graphics.Dispose(); $bitmap.Dispose()"
+}
+
+// screenshotCommand 根据操作系统和显示器生成 macOS 和 Windows 下的截图命令
+func (e *ExamService) screenshotCommand(displayID, displayName, file string) (*exec.Cmd, error) {
+	// 主显示器不需要查询显示器列表
+	if displayID == "" {
+		switch runtime.GOOS {
+		case "darwin":
+			return exec.Command("screencapture", "-x", "-t", "png", file), nil
+		case "windows":
+			return exec.Command("powershell", "-Command", windowsCaptureScript("[System.Windows.Forms.Screen]::PrimaryScreen.Bounds", file)), nil
+		default:
+			return nil, fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
+		}
+	}
+
+	displays, err := e.ListDisplays()
+	if err != nil {
+		return nil, err
+	}
+
+	if displayID == VirtualDesktopID {
+		switch runtime.GOOS {
+		case "darwin":
+			// screencapture -R 使用逻辑坐标
+			minX, minY, maxX, maxY := 0.0, 0.0, 0.0, 0.0
+			for i, d := range displays {
+				scale := math.Max(d.ScaleFactor, 1)
+				x, y := float64(d.X)/scale, float64(d.Y)/scale
+				right, bottom := x+float64(d.Width)/scale, y+float64(d.Height)/scale
+				if i == 0 {
+					minX, minY, maxX, maxY = x, y, right, bottom
+				}
+				minX, minY = math.Min(minX, x), math.Min(minY, y)
+				maxX, maxY = math.Max(maxX, right), math.Max(maxY, bottom)
+			}
+			rect := fmt.Sprintf("%d,%d,%d,%d", int(minX), int(minY), int(maxX-minX), int(maxY-minY))
+			return exec.Command("screencapture", "-x", "-t", "png", "-R", rect, file), nil
+		case "windows":
+			return exec.Command("powershell", "-Command", windowsCaptureScript("[System.Windows.Forms.SystemInformation]::VirtualScreen", file)), nil
+		default:
+			return nil, fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
+		}
+	}
+
+	display, err := e.findDisplay(displayID, displayName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("screencapture", "-x", "-t", "png", "-D", strconv.Itoa(display.index), file), nil
+	case "windows":
+		// 按设备名在 PowerShell 中重新查找显示器，得到DPI感知后的物理像素范围
+		name := strings.ReplaceAll(display.Name, "'", "''")
+		boundsExpr := "([System.Windows.Forms.Screen]::AllScreens | Where-Object { $_.DeviceName -eq '" + name + "' } | Select-Object -First 1).Bounds"
+		return exec.Command("powershell", "-Command", windowsCaptureScript(boundsExpr, file)), nil
+	default:
+		return nil, fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
+	}
+}
+
+// DisplaysResponse HTTP显示器列表响应结构
+type DisplaysResponse struct {
+	Success  bool          `json:"success"`
+	Message  string        `json:"message,omitempty"`
+	Displays []DisplayInfo `json:"displays,omitempty"`
+}
+
+// handleListDisplays 处理HTTP获取显示器列表请求
+func handleListDisplays(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许GET方法
+	if r.Method != "GET" {
+		http.Error(w, "只支持GET方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	response := DisplaysResponse{Success: true}
+	displays, err := examService.ListDisplays()
+	if err != nil {
+		response = DisplaysResponse{
+			Success: false,
+			Message: "获取显示器失败: " + err.Error(),
+		}
+	} else {
+		response.Displays = displays
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ParseExcelFile 解析Excel文件
+// sheetName 为空时读取第一个工作表
+func (e *ExamService) ParseExcelFile(filePath string, sheetName string, optionSeparator string, answerSeparator string) ([]AnswerItem, error) {
+	answers, _, _, err := e.parseExcel(filePath, ImportOptions{
+		SheetName:       sheetName,
+		OptionSeparator: optionSeparator,
+		AnswerSeparator: answerSeparator,
+	})
+	return answers, err
+}
+
+// parseExcel 解析Excel文件，返回诊断报告和实际使用的分隔符
+func (e *ExamService) parseExcel(filePath string, options ImportOptions) ([]AnswerItem, *ImportReport, DetectedSettings, error) {
+	settings := DetectedSettings{}
+
+	// excelize 仅支持 OOXML 格式，旧版 .xls 需先另存为 .xlsx
+	if strings.EqualFold(filepath.Ext(filePath), ".xls") {
+		return nil, nil, settings, fmt.Errorf("不支持旧版.xls格式，请另存为.xlsx后再导入")
+	}
+
+	// 打开文件
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, nil, settings, fmt.Errorf("无法打开文件: %v", err)
+	}
+	defer f.Close()
+
+	// 选择工作表
+	sheetName := options.SheetName
+	if sheetName == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, nil, settings, fmt.Errorf("文件中没有工作表")
+		}
+		sheetName = sheets[0]
+	} else if idx, err := f.GetSheetIndex(sheetName); err != nil || idx == -1 {
+		return nil, nil, settings, fmt.Errorf("工作表不存在: %s", sheetName)
+	}
+
+	rows, err := f.Rows(sheetName)
+	if err != nil {
+		return nil, nil, settings, fmt.Errorf("读取工作表失败: %v", err)
+	}
+	defer rows.Close()
+
+	// Excel 会省略行尾的空单元格，因此不检查列数
+	answers, report, err := e.parseTable(&excelRows{rows: rows}, options, false, &settings)
+	return answers, report, settings, err
+}
+
+// excelRows 从Excel工作表读取表格数据
+type excelRows struct {
+	rows *excelize.Rows
+	row  int
+}
+
+func (x *excelRows) Read() ([]string, int, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, io.EOF
+	}
+	x.row++
+
+	record, err := x.rows.Columns()
+	if err != nil {
+		return nil, x.row, err
+	}
+	return record, x.row, nil
+}
+
+// GetExcelSheets 获取Excel文件中的工作表列表
+func (e *ExamService) GetExcelSheets(filePath string) ([]string, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开文件: %v", err)
+	}
+	defer f.Close()
+
+	return f.GetSheetList(), nil
+}
+
+// ParseExcelRequest HTTP Excel解析请求结构
+type ParseExcelRequest struct {
+	FilePath        string `json:"filePath"`
+	SheetName       string `json:"sheetName"`
+	OptionSeparator string `json:"optionSeparator"`
+	AnswerSeparator string `json:"answerSeparator"`
+	Mapping         string `json:"mapping"` // 列映射预设名称
+	Lenient         bool   `json:"lenient"` // 宽松模式：跳过有问题的行并返回诊断报告
+}
+
+// handleParseExcel 处理HTTP Excel解析请求
+func handleParseExcel(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req ParseExcelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	// 调用解析方法，宽松模式下附带诊断报告
+	results, report, settings, err := examService.parseExcel(req.FilePath, ImportOptions{
+		SheetName:       req.SheetName,
+		OptionSeparator: req.OptionSeparator,
+		AnswerSeparator: req.AnswerSeparator,
+		Mapping:         req.Mapping,
+		Lenient:         req.Lenient,
+	})
+	if err != nil {
+		response := ParseCSVResponse{
+			Success: false,
+			Message: "Excel解析失败: " + err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	// 返回解析结果
+	response := ParseCSVResponse{
+		Success:  true,
+		Results:  results,
+		Report:   report,
+		Settings: &settings,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
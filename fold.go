@@ -0,0 +1,51 @@
+package main
+
+import "strings"
+
+// extraTraditionalPairs 常用字表之外补充的繁简对照，每两个字为一组：繁体在前，简体在后
+const extraTraditionalPairs = "試试確确屬属標标準准協协絡络係系憶忆護护醫医藥药療疗診诊斷断賬账帳帐價价貨货幣币銀银稅税費费嗎吗習习題题數数據据類类總总錄录圖图務务參参與与選选擇择項项錯错誤误對对論论證证變变規规範范測测驗验線线點点層层傳传輸输鏈链節节隊队採采構构運运態态況况級级響响質质權权處处"
+
+// simplifiedHomographs 常用字表中本身也是规范简体字的繁体字，如“显著”的著、“乾坤”的乾、姓氏於，
+// 转换会把不相关的词变成相同的字，因此不参与繁简转换
+const simplifiedHomographs = "著乾於"
+
+// traditionalToSimplified 繁体字到简体字的映射
+var traditionalToSimplified = buildTraditionalToSimplified()
+
+// buildTraditionalToSimplified 由常用字表和补充对照表建立繁简映射
+func buildTraditionalToSimplified() map[rune]rune {
+	mapping := map[rune]rune{}
+
+	simplified := []rune(commonSimplified)
+	traditional := []rune(commonTraditional)
+	for i := range traditional {
+		if i < len(simplified) && traditional[i] != simplified[i] && !strings.ContainsRune(simplifiedHomographs, traditional[i]) {
+			mapping[traditional[i]] = simplified[i]
+		}
+	}
+
+	extra := []rune(extraTraditionalPairs)
+	for i := 0; i+1 < len(extra); i += 2 {
+		mapping[extra[i]] = extra[i+1]
+	}
+
+	return mapping
+}
+
+// foldRune 将全角字符转换为半角，繁体字转换为简体
+// 转换前后始终是一个字符对应一个字符，便于将匹配位置映射回原文；
+// 全角减号保持不变，标准化时它作为标点被移除，而半角减号保留在文本中
+func foldRune(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r == '－':
+		return r
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	}
+	if s, ok := traditionalToSimplified[r]; ok {
+		return s
+	}
+	return r
+}
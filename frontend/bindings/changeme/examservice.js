@@ -16,12 +16,164 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 import * as $models from "./models.js";
 
 /**
- * GetGlobalAnswers 获取全局答案数据
+ * Capture 截取显示器并缓存，返回截图信息，displayID 的含义同 TakeDisplayScreenshot
+ * @param {string} displayID
+ * @returns {$CancellablePromise<$models.Capture>}
+ */
+export function Capture(displayID) {
+    return $Call.ByID(4087258564, displayID).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * CaptureAndSearch 截图并按区域裁剪，OCR识别后解析题目结构并在题库中搜索，返回结果和各阶段耗时
+ * @param {$models.CaptureSearchRequest} request
+ * @returns {$CancellablePromise<$models.CaptureSearchResult>}
+ */
+export function CaptureAndSearch(request) {
+    return $Call.ByID(2204916681, request).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
+/**
+ * DeleteBank 删除题库
+ * @param {string} id
+ * @returns {$CancellablePromise<void>}
+ */
+export function DeleteBank(id) {
+    return $Call.ByID(176373067, id);
+}
+
+/**
+ * DeleteCaptureRegion 删除截图区域
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function DeleteCaptureRegion(name) {
+    return $Call.ByID(2218279669, name);
+}
+
+/**
+ * DeleteColumnMapping 删除列映射预设
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function DeleteColumnMapping(name) {
+    return $Call.ByID(589345087, name);
+}
+
+/**
+ * DetectFileSettings 自动检测文件的编码、CSV分隔符以及选项和答案分隔符
+ * @param {string} filePath
+ * @returns {$CancellablePromise<$models.DetectedSettings>}
+ */
+export function DetectFileSettings(filePath) {
+    return $Call.ByID(939277738, filePath).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * ExportBank 将题库导出为JSON、JSONL或YAML格式的文本，id 为空时导出所有启用题库中的题目
+ * @param {string} id
+ * @param {string} format
+ * @returns {$CancellablePromise<string>}
+ */
+export function ExportBank(id, format) {
+    return $Call.ByID(4024207618, id, format);
+}
+
+/**
+ * ExportBankFile 将题库导出到文件，format 为空时按扩展名确定格式
+ * @param {string} id
+ * @param {string} filePath
+ * @param {string} format
+ * @returns {$CancellablePromise<void>}
+ */
+export function ExportBankFile(id, filePath, format) {
+    return $Call.ByID(2202335204, id, filePath, format);
+}
+
+/**
+ * GetCaptureImage 获取缓存中的截图，按区域裁剪后以PNG格式的 data URL 返回，用于前端显示
+ * @param {string} id
+ * @param {$models.ScreenshotArea} area
+ * @returns {$CancellablePromise<string>}
+ */
+export function GetCaptureImage(id, area) {
+    return $Call.ByID(1953679421, id, area);
+}
+
+/**
+ * GetConfusables 获取用户自定义的易混淆字符组
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetConfusables() {
+    return $Call.ByID(1514771605).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * GetExcelSheets 获取Excel文件中的工作表列表
+ * @param {string} filePath
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetExcelSheets(filePath) {
+    return $Call.ByID(65162961, filePath).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * GetGlobalAnswers 获取所有启用题库中的答案数据
  * @returns {$CancellablePromise<$models.AnswerItem[]>}
  */
 export function GetGlobalAnswers() {
     return $Call.ByID(950795820).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType5($result);
+    }));
+}
+
+/**
+ * GetPreprocessProfiles 获取内置的预处理方案
+ * @returns {$CancellablePromise<{ [_: string]: $models.PreprocessProfile }>}
+ */
+export function GetPreprocessProfiles() {
+    return $Call.ByID(3426230226).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType7($result);
+    }));
+}
+
+/**
+ * GetScreenshotDiagnostics 检测截图环境，列出各截图后端是否可用
+ * @returns {$CancellablePromise<$models.ScreenshotDiagnostics>}
+ */
+export function GetScreenshotDiagnostics() {
+    return $Call.ByID(3960313518).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType8($result);
+    }));
+}
+
+/**
+ * GetUserDictionary 获取用户词典中的词语
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function GetUserDictionary() {
+    return $Call.ByID(34226213).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * GetWatchStatus 获取监视状态
+ * @returns {$CancellablePromise<$models.WatchStatus>}
+ */
+export function GetWatchStatus() {
+    return $Call.ByID(69485403).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType9($result);
     }));
 }
 
@@ -33,6 +185,113 @@ export function HideWindow() {
     return $Call.ByID(4117485866);
 }
 
+/**
+ * ImportBank 将解析出的答案导入为一个新题库
+ * name 为空时使用来源文件名
+ * @param {string} name
+ * @param {string} sourceFile
+ * @param {$models.AnswerItem[]} answers
+ * @returns {$CancellablePromise<$models.BankInfo>}
+ */
+export function ImportBank(name, sourceFile, answers) {
+    return $Call.ByID(2173579089, name, sourceFile, answers).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType10($result);
+    }));
+}
+
+/**
+ * ImportFile 按扩展名解析CSV、Excel或JSON、JSONL、YAML题库文件
+ * @param {string} filePath
+ * @param {$models.ImportOptions} options
+ * @returns {$CancellablePromise<$models.ImportResult>}
+ */
+export function ImportFile(filePath, options) {
+    return $Call.ByID(691715093, filePath, options).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType11($result);
+    }));
+}
+
+/**
+ * ImportScans 识别扫描件中的题目，生成待审核的题目草稿
+ * path 可以是图片目录（按文件名中的数字顺序排列）、单张图片或PDF文件（通过 pdftoppm 渲染为图片）
+ * @param {$models.ScanImportRequest} request
+ * @returns {$CancellablePromise<$models.ScanImportResult>}
+ */
+export function ImportScans(request) {
+    return $Call.ByID(1989326043, request).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType12($result);
+    }));
+}
+
+/**
+ * ListBanks 获取所有题库
+ * @returns {$CancellablePromise<$models.BankInfo[]>}
+ */
+export function ListBanks() {
+    return $Call.ByID(1760187765).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType13($result);
+    }));
+}
+
+/**
+ * ListCaptureRegions 获取所有已保存的截图区域
+ * @returns {$CancellablePromise<$models.CaptureRegion[]>}
+ */
+export function ListCaptureRegions() {
+    return $Call.ByID(1623727973).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType15($result);
+    }));
+}
+
+/**
+ * ListColumnMappings 获取所有列映射预设
+ * @returns {$CancellablePromise<$models.ColumnMapping[]>}
+ */
+export function ListColumnMappings() {
+    return $Call.ByID(3139943799).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType17($result);
+    }));
+}
+
+/**
+ * ListDisplays 获取所有显示器
+ * @returns {$CancellablePromise<$models.DisplayInfo[]>}
+ */
+export function ListDisplays() {
+    return $Call.ByID(2705541121).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType19($result);
+    }));
+}
+
+/**
+ * ListOCREngines 获取所有已注册的OCR模式
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function ListOCREngines() {
+    return $Call.ByID(3410703673).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * ListTokenizers 获取所有已注册的分词器名称
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function ListTokenizers() {
+    return $Call.ByID(2706006924).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * LoadScreenshotFile 读取图片文件，返回与截图相同的PNG格式 data URL
+ * @param {string} path
+ * @returns {$CancellablePromise<string>}
+ */
+export function LoadScreenshotFile(path) {
+    return $Call.ByID(2457626820, path);
+}
+
 /**
  * NextQuestion 下一题功能
  * @param {$models.ScreenshotArea} area
@@ -51,12 +310,13 @@ export function NextQuestion(area, config) {
  */
 export function OpenFileDialog(title, fileType) {
     return $Call.ByID(883910656, title, fileType).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType2($result);
+        return $$createType20($result);
     }));
 }
 
 /**
  * ParseCSVFile 解析CSV文件
+ * encoding、optionSeparator、answerSeparator 可传 auto 自动检测，字段分隔符始终自动检测
  * @param {string} filePath
  * @param {string} encoding
  * @param {string} optionSeparator
@@ -65,7 +325,47 @@ export function OpenFileDialog(title, fileType) {
  */
 export function ParseCSVFile(filePath, encoding, optionSeparator, answerSeparator) {
     return $Call.ByID(1360511181, filePath, encoding, optionSeparator, answerSeparator).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType5($result);
+    }));
+}
+
+/**
+ * ParseCSVFileAuto 自动检测编码和分隔符并解析CSV文件
+ * @param {string} filePath
+ * @returns {$CancellablePromise<$models.ImportResult>}
+ */
+export function ParseCSVFileAuto(filePath) {
+    return $Call.ByID(1260191246, filePath).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType11($result);
+    }));
+}
+
+/**
+ * ParseCSVFileLenient 宽松模式解析CSV文件，跳过有问题的行并返回逐行诊断报告
+ * @param {string} filePath
+ * @param {string} encoding
+ * @param {string} optionSeparator
+ * @param {string} answerSeparator
+ * @returns {$CancellablePromise<$models.ImportResult>}
+ */
+export function ParseCSVFileLenient(filePath, encoding, optionSeparator, answerSeparator) {
+    return $Call.ByID(3794745652, filePath, encoding, optionSeparator, answerSeparator).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType11($result);
+    }));
+}
+
+/**
+ * ParseExcelFile 解析Excel文件
+ * sheetName 为空时读取第一个工作表
+ * @param {string} filePath
+ * @param {string} sheetName
+ * @param {string} optionSeparator
+ * @param {string} answerSeparator
+ * @returns {$CancellablePromise<$models.AnswerItem[]>}
+ */
+export function ParseExcelFile(filePath, sheetName, optionSeparator, answerSeparator) {
+    return $Call.ByID(1250604610, filePath, sheetName, optionSeparator, answerSeparator).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType5($result);
     }));
 }
 
@@ -80,6 +380,29 @@ export function PerformOCR(area, config) {
 }
 
 /**
+ * PerformOCRParsed 执行OCR识别并解析为结构化题目
+ * @param {$models.ScreenshotArea} area
+ * @param {$models.OCRConfig} config
+ * @returns {$CancellablePromise<$models.ParsedQuestion>}
+ */
+export function PerformOCRParsed(area, config) {
+    return $Call.ByID(1362754924, area, config).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType21($result);
+    }));
+}
+
+/**
+ * PreviewPreprocess 裁剪截图并按配置预处理，返回处理后的图片，用于调试预处理方案
+ * @param {$models.ScreenshotArea} area
+ * @param {$models.OCRConfig} config
+ * @returns {$CancellablePromise<string>}
+ */
+export function PreviewPreprocess(area, config) {
+    return $Call.ByID(3105127576, area, config);
+}
+
+/**
+ * ReadFileContent 读取文件内容，encoding 为 auto 时自动检测编码
  * @param {string} filePath
  * @param {string} encoding
  * @returns {$CancellablePromise<string>}
@@ -89,14 +412,91 @@ export function ReadFileContent(filePath, encoding) {
 }
 
 /**
+ * ReleaseCapture 从缓存中删除截图
+ * @param {string} id
+ * @returns {$CancellablePromise<void>}
+ */
+export function ReleaseCapture(id) {
+    return $Call.ByID(2970772055, id);
+}
+
+/**
+ * RenameBank 重命名题库
+ * @param {string} id
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function RenameBank(id, name) {
+    return $Call.ByID(1658063390, id, name);
+}
+
+/**
+ * SaveCaptureRegion 保存截图区域
+ * 未提供显示器名称和尺寸时从当前显示器补全，以便显示器变化后重新匹配和换算
+ * @param {$models.CaptureRegion} region
+ * @returns {$CancellablePromise<void>}
+ */
+export function SaveCaptureRegion(region) {
+    return $Call.ByID(1806912259, region);
+}
+
+/**
+ * SaveColumnMapping 保存列映射预设
+ * @param {$models.ColumnMapping} mapping
+ * @returns {$CancellablePromise<void>}
+ */
+export function SaveColumnMapping(mapping) {
+    return $Call.ByID(1889693753, mapping);
+}
+
+/**
+ * SearchAnswers 在给定题目中搜索答案，标签、难度和来源条件先于准确度筛选生效
  * @param {$models.AnswerItem[]} answers
  * @param {string} query
- * @param {$models.AccuracyFilters} filters
+ * @param {$models.SearchFilters} filters
  * @returns {$CancellablePromise<$models.SearchResult[]>}
  */
 export function SearchAnswers(answers, query, filters) {
     return $Call.ByID(1576479801, answers, query, filters).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType4($result);
+        return $$createType23($result);
+    }));
+}
+
+/**
+ * SearchBanks 在所有启用的题库中搜索答案
+ * @param {string} query
+ * @param {$models.SearchFilters} filters
+ * @returns {$CancellablePromise<$models.SearchResult[]>}
+ */
+export function SearchBanks(query, filters) {
+    return $Call.ByID(43492777, query, filters).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType23($result);
+    }));
+}
+
+/**
+ * SearchByOptions 将识别文本拆分为题干和选项，按题干与选项组合在所有启用的题库中搜索
+ * 题干相近但选项不同的题目可以据此区分；识别不出选项时退回普通搜索
+ * @param {string} query
+ * @param {$models.SearchFilters} filters
+ * @returns {$CancellablePromise<$models.SearchResult[]>}
+ */
+export function SearchByOptions(query, filters) {
+    return $Call.ByID(3834709755, query, filters).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType23($result);
+    }));
+}
+
+/**
+ * SearchParsedQuestion 按结构化题目在所有启用的题库中搜索
+ * 有选项时按题干与选项组合打分，否则按题干搜索；题型提示与题库题型不一致的结果分数略微降低
+ * @param {$models.ParsedQuestion} q
+ * @param {$models.SearchFilters} filters
+ * @returns {$CancellablePromise<$models.SearchResult[]>}
+ */
+export function SearchParsedQuestion(q, filters) {
+    return $Call.ByID(3825354883, q, filters).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType23($result);
     }));
 }
 
@@ -107,12 +507,32 @@ export function SearchAnswers(answers, query, filters) {
  */
 export function SelectArea(screenshotData) {
     return $Call.ByID(2467347915, screenshotData).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType5($result);
+        return $$createType24($result);
     }));
 }
 
 /**
- * SetGlobalAnswers 设置全局答案数据
+ * SetBankEnabled 启用或停用题库，停用的题库不参与搜索
+ * @param {string} id
+ * @param {boolean} enabled
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetBankEnabled(id, enabled) {
+    return $Call.ByID(479247785, id, enabled);
+}
+
+/**
+ * SetConfusables 保存用户自定义的易混淆字符组并立即生效
+ * 每组为若干互相易混淆的字符，可在其后以空格分隔替换代价（0~1）
+ * @param {string[]} groups
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetConfusables(groups) {
+    return $Call.ByID(2851830697, groups);
+}
+
+/**
+ * SetGlobalAnswers 设置全局答案数据，作为一个新题库保存到本地
  * @param {$models.AnswerItem[]} answers
  * @returns {$CancellablePromise<void>}
  */
@@ -120,6 +540,34 @@ export function SetGlobalAnswers(answers) {
     return $Call.ByID(47794008, answers);
 }
 
+/**
+ * SetScreenshotFile 设置截图文件，设置后所有截图都读取该文件而不截取屏幕，path 为空时恢复截取屏幕
+ * @param {string} path
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetScreenshotFile(path) {
+    return $Call.ByID(2902052748, path);
+}
+
+/**
+ * SetTokenizer 切换搜索使用的分词器
+ * @param {string} name
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetTokenizer(name) {
+    return $Call.ByID(3684758131, name);
+}
+
+/**
+ * SetUserDictionary 保存用户词典并立即生效
+ * 每项为一个词语，可在词语后以空格分隔词频
+ * @param {string[]} words
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetUserDictionary(words) {
+    return $Call.ByID(130317857, words);
+}
+
 /**
  * ShowWindow 显示应用窗口
  * @returns {$CancellablePromise<void>}
@@ -129,7 +577,34 @@ export function ShowWindow() {
 }
 
 /**
- * TakeScreenshot 截取屏幕
+ * StartWatch 开始监视截图区域，区域内容变化后自动识别和搜索，结果通过 watch:event 事件推送
+ * @param {$models.WatchRequest} request
+ * @returns {$CancellablePromise<void>}
+ */
+export function StartWatch(request) {
+    return $Call.ByID(199499029, request);
+}
+
+/**
+ * StopWatch 停止监视截图区域
+ * @returns {$CancellablePromise<void>}
+ */
+export function StopWatch() {
+    return $Call.ByID(2409915325);
+}
+
+/**
+ * TakeDisplayScreenshot 截取指定显示器，返回PNG格式的 data URL
+ * displayID 为空时截取主显示器，为 VirtualDesktopID 时截取整个虚拟桌面
+ * @param {string} displayID
+ * @returns {$CancellablePromise<string>}
+ */
+export function TakeDisplayScreenshot(displayID) {
+    return $Call.ByID(2651633065, displayID);
+}
+
+/**
+ * TakeScreenshot 截取主显示器，返回PNG格式的 data URL
  * @returns {$CancellablePromise<string>}
  */
 export function TakeScreenshot() {
@@ -162,9 +637,28 @@ export function TestOCRConnection(config) {
 }
 
 // Private type creation functions
-const $$createType0 = $models.AnswerItem.createFrom;
-const $$createType1 = $Create.Array($$createType0);
-const $$createType2 = $models.FileDialogResult.createFrom;
-const $$createType3 = $models.SearchResult.createFrom;
-const $$createType4 = $Create.Array($$createType3);
-const $$createType5 = $models.ScreenshotArea.createFrom;
+const $$createType0 = $models.Capture.createFrom;
+const $$createType1 = $models.CaptureSearchResult.createFrom;
+const $$createType2 = $models.DetectedSettings.createFrom;
+const $$createType3 = $Create.Array($Create.Any);
+const $$createType4 = $models.AnswerItem.createFrom;
+const $$createType5 = $Create.Array($$createType4);
+const $$createType6 = $models.PreprocessProfile.createFrom;
+const $$createType7 = $Create.Map($Create.Any, $$createType6);
+const $$createType8 = $models.ScreenshotDiagnostics.createFrom;
+const $$createType9 = $models.WatchStatus.createFrom;
+const $$createType10 = $models.BankInfo.createFrom;
+const $$createType11 = $models.ImportResult.createFrom;
+const $$createType12 = $models.ScanImportResult.createFrom;
+const $$createType13 = $Create.Array($$createType10);
+const $$createType14 = $models.CaptureRegion.createFrom;
+const $$createType15 = $Create.Array($$createType14);
+const $$createType16 = $models.ColumnMapping.createFrom;
+const $$createType17 = $Create.Array($$createType16);
+const $$createType18 = $models.DisplayInfo.createFrom;
+const $$createType19 = $Create.Array($$createType18);
+const $$createType20 = $models.FileDialogResult.createFrom;
+const $$createType21 = $models.ParsedQuestion.createFrom;
+const $$createType22 = $models.SearchResult.createFrom;
+const $$createType23 = $Create.Array($$createType22);
+const $$createType24 = $models.ScreenshotArea.createFrom;
@@ -10,8 +10,33 @@ export {
 export {
     AccuracyFilters,
     AnswerItem,
+    BankInfo,
+    Capture,
+    CaptureRegion,
+    CaptureSearchRequest,
+    CaptureSearchResult,
+    ColumnMapping,
+    DetectedSettings,
+    DisplayInfo,
     FileDialogResult,
+    HeaderError,
+    ImportIssue,
+    ImportOptions,
+    ImportReport,
+    ImportResult,
     OCRConfig,
+    ParsedQuestion,
+    PreprocessProfile,
+    ScanImportRequest,
+    ScanImportResult,
+    ScanPage,
     ScreenshotArea,
-    SearchResult
+    ScreenshotBackendStatus,
+    ScreenshotDiagnostics,
+    SearchFilters,
+    SearchResult,
+    StageTimings,
+    TesseractConfig,
+    WatchRequest,
+    WatchStatus
 } from "./models.js";
@@ -6,6 +6,10 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../time/models.js";
+
 /**
  * SearchAnswers 搜索答案
  * AccuracyFilters 准确度筛选参数
@@ -96,6 +100,46 @@ export class AnswerItem {
              */
             this["answer"] = [];
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * 解析
+             * @member
+             * @type {string | undefined}
+             */
+            this["explanation"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 标签，如章节、知识点
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["tags"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 难度
+             * @member
+             * @type {string | undefined}
+             */
+            this["difficulty"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 来源，如教材页码、真题年份
+             * @member
+             * @type {string | undefined}
+             */
+            this["source"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 未映射到标准字段的列，key为表头
+             * @member
+             * @type {{ [_: string]: string } | undefined}
+             */
+            this["extra"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -108,6 +152,8 @@ export class AnswerItem {
     static createFrom($$source = {}) {
         const $$createField2_0 = $$createType0;
         const $$createField3_0 = $$createType0;
+        const $$createField5_0 = $$createType0;
+        const $$createField8_0 = $$createType1;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("options" in $$parsedSource) {
             $$parsedSource["options"] = $$createField2_0($$parsedSource["options"]);
@@ -115,120 +161,200 @@ export class AnswerItem {
         if ("answer" in $$parsedSource) {
             $$parsedSource["answer"] = $$createField3_0($$parsedSource["answer"]);
         }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField5_0($$parsedSource["tags"]);
+        }
+        if ("extra" in $$parsedSource) {
+            $$parsedSource["extra"] = $$createField8_0($$parsedSource["extra"]);
+        }
         return new AnswerItem(/** @type {Partial<AnswerItem>} */($$parsedSource));
     }
 }
 
 /**
- * FileDialogResult 文件对话框结果
+ * BankInfo 题库信息
  */
-export class FileDialogResult {
+export class BankInfo {
     /**
-     * Creates a new FileDialogResult instance.
-     * @param {Partial<FileDialogResult>} [$$source = {}] - The source object to create the FileDialogResult.
+     * Creates a new BankInfo instance.
+     * @param {Partial<BankInfo>} [$$source = {}] - The source object to create the BankInfo.
      */
     constructor($$source = {}) {
-        if (!("filePath" in $$source)) {
+        if (!("id" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["filePath"] = "";
+            this["id"] = "";
         }
-        if (!("success" in $$source)) {
+        if (!("name" in $$source)) {
+            /**
+             * 题库名称
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("sourceFile" in $$source)) {
+            /**
+             * 导入来源文件
+             * @member
+             * @type {string}
+             */
+            this["sourceFile"] = "";
+        }
+        if (!("importedAt" in $$source)) {
+            /**
+             * 导入时间
+             * @member
+             * @type {time$0.Time}
+             */
+            this["importedAt"] = null;
+        }
+        if (!("itemCount" in $$source)) {
+            /**
+             * 题目数量
+             * @member
+             * @type {number}
+             */
+            this["itemCount"] = 0;
+        }
+        if (!("enabled" in $$source)) {
             /**
+             * 是否参与搜索
              * @member
              * @type {boolean}
              */
-            this["success"] = false;
+            this["enabled"] = false;
         }
-        if (/** @type {any} */(false)) {
+        if (!("isDefault" in $$source)) {
             /**
+             * 是否为 SetGlobalAnswers 写入的默认题库
              * @member
-             * @type {string | undefined}
+             * @type {boolean}
              */
-            this["error"] = undefined;
+            this["isDefault"] = false;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new FileDialogResult instance from a string or object.
+     * Creates a new BankInfo instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {FileDialogResult}
+     * @returns {BankInfo}
      */
     static createFrom($$source = {}) {
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new FileDialogResult(/** @type {Partial<FileDialogResult>} */($$parsedSource));
+        return new BankInfo(/** @type {Partial<BankInfo>} */($$parsedSource));
     }
 }
 
 /**
- * OCRConfig OCR配置
+ * Capture 缓存中的截图，HTTP和前端接口通过 ID 引用截图，不再传递图片数据
  */
-export class OCRConfig {
+export class Capture {
     /**
-     * Creates a new OCRConfig instance.
-     * @param {Partial<OCRConfig>} [$$source = {}] - The source object to create the OCRConfig.
+     * Creates a new Capture instance.
+     * @param {Partial<Capture>} [$$source = {}] - The source object to create the Capture.
      */
     constructor($$source = {}) {
-        if (!("mode" in $$source)) {
+        if (!("id" in $$source)) {
             /**
-             * "online" 或 "local"
+             * 截图标识
              * @member
              * @type {string}
              */
-            this["mode"] = "";
+            this["id"] = "";
         }
-        if (!("url" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
-             * 在线OCR URL
+             * 截取的显示器，为空时表示主显示器
              * @member
-             * @type {string}
+             * @type {string | undefined}
              */
-            this["url"] = "";
+            this["displayId"] = undefined;
         }
-        if (!("apiKey" in $$source)) {
+        if (!("width" in $$source)) {
             /**
-             * API密钥
+             * 宽度
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["apiKey"] = "";
+            this["width"] = 0;
         }
-        if (!("status" in $$source)) {
+        if (!("height" in $$source)) {
             /**
-             * 连接状态
+             * 高度
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["status"] = "";
+            this["height"] = 0;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new OCRConfig instance from a string or object.
+     * Creates a new Capture instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {OCRConfig}
+     * @returns {Capture}
      */
     static createFrom($$source = {}) {
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new OCRConfig(/** @type {Partial<OCRConfig>} */($$parsedSource));
+        return new Capture(/** @type {Partial<Capture>} */($$parsedSource));
     }
 }
 
 /**
- * ScreenshotArea 截图区域
+ * CaptureRegion 已保存的截图区域，坐标为所在显示器截图中的物理像素
  */
-export class ScreenshotArea {
+export class CaptureRegion {
     /**
-     * Creates a new ScreenshotArea instance.
-     * @param {Partial<ScreenshotArea>} [$$source = {}] - The source object to create the ScreenshotArea.
+     * Creates a new CaptureRegion instance.
+     * @param {Partial<CaptureRegion>} [$$source = {}] - The source object to create the CaptureRegion.
      */
     constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * 区域名称
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 所在显示器标识，为空时表示主显示器
+             * @member
+             * @type {string | undefined}
+             */
+            this["displayId"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 所在显示器名称，显示器标识变化时用于重新匹配
+             * @member
+             * @type {string | undefined}
+             */
+            this["displayName"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 保存区域时显示器截图的宽度
+             * @member
+             * @type {number | undefined}
+             */
+            this["displayWidth"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 保存区域时显示器截图的高度
+             * @member
+             * @type {number | undefined}
+             */
+            this["displayHeight"] = undefined;
+        }
         if (!("x" in $$source)) {
             /**
              * @member
@@ -257,118 +383,1897 @@ export class ScreenshotArea {
              */
             this["height"] = 0;
         }
-        if (!("image" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CaptureRegion instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {CaptureRegion}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new CaptureRegion(/** @type {Partial<CaptureRegion>} */($$parsedSource));
+    }
+}
+
+/**
+ * CaptureSearchRequest 一次截图、识别并搜索的请求
+ */
+export class CaptureSearchRequest {
+    /**
+     * Creates a new CaptureSearchRequest instance.
+     * @param {Partial<CaptureSearchRequest>} [$$source = {}] - The source object to create the CaptureSearchRequest.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
             /**
-             * base64编码的图片
+             * 已保存的截图区域名称
              * @member
-             * @type {string}
+             * @type {string | undefined}
              */
-            this["image"] = "";
+            this["region"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 未指定区域名称时使用的区域
+             * @member
+             * @type {CaptureRegion | null | undefined}
+             */
+            this["area"] = undefined;
+        }
+        if (!("config" in $$source)) {
+            /**
+             * OCR配置
+             * @member
+             * @type {OCRConfig}
+             */
+            this["config"] = (new OCRConfig());
+        }
+        if (!("filters" in $$source)) {
+            /**
+             * 搜索筛选条件
+             * @member
+             * @type {SearchFilters}
+             */
+            this["filters"] = (new SearchFilters());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 截图前是否隐藏应用窗口
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["hideWindow"] = undefined;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new ScreenshotArea instance from a string or object.
+     * Creates a new CaptureSearchRequest instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {ScreenshotArea}
+     * @returns {CaptureSearchRequest}
      */
     static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType3;
+        const $$createField2_0 = $$createType4;
+        const $$createField3_0 = $$createType5;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new ScreenshotArea(/** @type {Partial<ScreenshotArea>} */($$parsedSource));
+        if ("area" in $$parsedSource) {
+            $$parsedSource["area"] = $$createField1_0($$parsedSource["area"]);
+        }
+        if ("config" in $$parsedSource) {
+            $$parsedSource["config"] = $$createField2_0($$parsedSource["config"]);
+        }
+        if ("filters" in $$parsedSource) {
+            $$parsedSource["filters"] = $$createField3_0($$parsedSource["filters"]);
+        }
+        return new CaptureSearchRequest(/** @type {Partial<CaptureSearchRequest>} */($$parsedSource));
     }
 }
 
 /**
- * SearchResult 搜索结果
+ * CaptureSearchResult 一次截图、识别并搜索的结果
  */
-export class SearchResult {
+export class CaptureSearchResult {
     /**
-     * Creates a new SearchResult instance.
-     * @param {Partial<SearchResult>} [$$source = {}] - The source object to create the SearchResult.
+     * Creates a new CaptureSearchResult instance.
+     * @param {Partial<CaptureSearchResult>} [$$source = {}] - The source object to create the CaptureSearchResult.
      */
     constructor($$source = {}) {
-        if (!("item" in $$source)) {
+        if (!("region" in $$source)) {
             /**
+             * 使用的截图区域
              * @member
-             * @type {AnswerItem}
+             * @type {CaptureRegion}
              */
-            this["item"] = (new AnswerItem());
+            this["region"] = (new CaptureRegion());
         }
-        if (!("score" in $$source)) {
+        if (!("text" in $$source)) {
             /**
-             * 匹配度
+             * 识别出的文字
              * @member
-             * @type {number}
+             * @type {string}
              */
-            this["score"] = 0;
+            this["text"] = "";
         }
-        if (!("matched" in $$source)) {
+        if (!("parsed" in $$source)) {
             /**
-             * 匹配的文本
+             * 解析出的题目结构
              * @member
-             * @type {string}
+             * @type {ParsedQuestion}
              */
-            this["matched"] = "";
+            this["parsed"] = (new ParsedQuestion());
         }
-        if (!("questionMatches" in $$source)) {
+        if (!("results" in $$source)) {
             /**
-             * 题目匹配位置
+             * 按匹配度排序的搜索结果
              * @member
-             * @type {number[]}
+             * @type {SearchResult[]}
              */
-            this["questionMatches"] = [];
+            this["results"] = [];
         }
-        if (!("optionMatches" in $$source)) {
+        if (!("timings" in $$source)) {
             /**
-             * 选项匹配位置，key为选项文本
+             * 各阶段耗时
              * @member
-             * @type {{ [_: string]: number[] }}
+             * @type {StageTimings}
              */
-            this["optionMatches"] = {};
+            this["timings"] = (new StageTimings());
         }
-        if (!("answerMatches" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CaptureSearchResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {CaptureSearchResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType2;
+        const $$createField2_0 = $$createType6;
+        const $$createField3_0 = $$createType8;
+        const $$createField4_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("region" in $$parsedSource) {
+            $$parsedSource["region"] = $$createField0_0($$parsedSource["region"]);
+        }
+        if ("parsed" in $$parsedSource) {
+            $$parsedSource["parsed"] = $$createField2_0($$parsedSource["parsed"]);
+        }
+        if ("results" in $$parsedSource) {
+            $$parsedSource["results"] = $$createField3_0($$parsedSource["results"]);
+        }
+        if ("timings" in $$parsedSource) {
+            $$parsedSource["timings"] = $$createField4_0($$parsedSource["timings"]);
+        }
+        return new CaptureSearchResult(/** @type {Partial<CaptureSearchResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * ColumnMapping 列映射配置，将任意表头映射到答案项的标准字段
+ */
+export class ColumnMapping {
+    /**
+     * Creates a new ColumnMapping instance.
+     * @param {Partial<ColumnMapping>} [$$source = {}] - The source object to create the ColumnMapping.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
             /**
-             * 答案匹配位置（不使用）
+             * 预设名称
              * @member
-             * @type {number[]}
+             * @type {string}
              */
-            this["answerMatches"] = [];
+            this["name"] = "";
+        }
+        if (!("aliases" in $$source)) {
+            /**
+             * 标准字段 -> 表头别名，标准字段为 类型/题目/选项/答案/解析/标签/难度/来源
+             * @member
+             * @type {{ [_: string]: string[] }}
+             */
+            this["aliases"] = {};
+        }
+        if (!("optionColumns" in $$source)) {
+            /**
+             * 每个选项单独一列时的表头，按选项顺序排列；为空时自动识别 A/B/C/D 列
+             * @member
+             * @type {string[]}
+             */
+            this["optionColumns"] = [];
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new SearchResult instance from a string or object.
+     * Creates a new ColumnMapping instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {SearchResult}
+     * @returns {ColumnMapping}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType1;
-        const $$createField3_0 = $$createType2;
-        const $$createField4_0 = $$createType3;
-        const $$createField5_0 = $$createType2;
+        const $$createField1_0 = $$createType10;
+        const $$createField2_0 = $$createType0;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("item" in $$parsedSource) {
-            $$parsedSource["item"] = $$createField0_0($$parsedSource["item"]);
-        }
-        if ("questionMatches" in $$parsedSource) {
-            $$parsedSource["questionMatches"] = $$createField3_0($$parsedSource["questionMatches"]);
+        if ("aliases" in $$parsedSource) {
+            $$parsedSource["aliases"] = $$createField1_0($$parsedSource["aliases"]);
         }
-        if ("optionMatches" in $$parsedSource) {
-            $$parsedSource["optionMatches"] = $$createField4_0($$parsedSource["optionMatches"]);
+        if ("optionColumns" in $$parsedSource) {
+            $$parsedSource["optionColumns"] = $$createField2_0($$parsedSource["optionColumns"]);
         }
-        if ("answerMatches" in $$parsedSource) {
-            $$parsedSource["answerMatches"] = $$createField5_0($$parsedSource["answerMatches"]);
+        return new ColumnMapping(/** @type {Partial<ColumnMapping>} */($$parsedSource));
+    }
+}
+
+/**
+ * DetectedSettings 自动检测出的文件导入设置
+ */
+export class DetectedSettings {
+    /**
+     * Creates a new DetectedSettings instance.
+     * @param {Partial<DetectedSettings>} [$$source = {}] - The source object to create the DetectedSettings.
+     */
+    constructor($$source = {}) {
+        if (!("encoding" in $$source)) {
+            /**
+             * 文件编码
+             * @member
+             * @type {string}
+             */
+            this["encoding"] = "";
+        }
+        if (!("hasBom" in $$source)) {
+            /**
+             * 是否带有BOM
+             * @member
+             * @type {boolean}
+             */
+            this["hasBom"] = false;
+        }
+        if (!("delimiter" in $$source)) {
+            /**
+             * CSV字段分隔符
+             * @member
+             * @type {string}
+             */
+            this["delimiter"] = "";
+        }
+        if (!("optionSeparator" in $$source)) {
+            /**
+             * 建议的选项分隔符，转义形式如 \n
+             * @member
+             * @type {string}
+             */
+            this["optionSeparator"] = "";
+        }
+        if (!("answerSeparator" in $$source)) {
+            /**
+             * 建议的答案分隔符，转义形式如 \n
+             * @member
+             * @type {string}
+             */
+            this["answerSeparator"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DetectedSettings instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DetectedSettings}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DetectedSettings(/** @type {Partial<DetectedSettings>} */($$parsedSource));
+    }
+}
+
+/**
+ * DisplayInfo 显示器信息，位置和尺寸为物理像素
+ */
+export class DisplayInfo {
+    /**
+     * Creates a new DisplayInfo instance.
+     * @param {Partial<DisplayInfo>} [$$source = {}] - The source object to create the DisplayInfo.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * 显示器标识
+             * @member
+             * @type {string}
+             */
+            this["id"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * 显示器名称，Windows 下为设备名，如 \\.\DISPLAY1
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("x" in $$source)) {
+            /**
+             * 在虚拟桌面中的位置
+             * @member
+             * @type {number}
+             */
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["y"] = 0;
+        }
+        if (!("width" in $$source)) {
+            /**
+             * 宽度
+             * @member
+             * @type {number}
+             */
+            this["width"] = 0;
+        }
+        if (!("height" in $$source)) {
+            /**
+             * 高度
+             * @member
+             * @type {number}
+             */
+            this["height"] = 0;
+        }
+        if (!("scaleFactor" in $$source)) {
+            /**
+             * 缩放比例（DPI/96）
+             * @member
+             * @type {number}
+             */
+            this["scaleFactor"] = 0;
+        }
+        if (!("isPrimary" in $$source)) {
+            /**
+             * 是否为主显示器
+             * @member
+             * @type {boolean}
+             */
+            this["isPrimary"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DisplayInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DisplayInfo}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DisplayInfo(/** @type {Partial<DisplayInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * FileDialogResult 文件对话框结果
+ */
+export class FileDialogResult {
+    /**
+     * Creates a new FileDialogResult instance.
+     * @param {Partial<FileDialogResult>} [$$source = {}] - The source object to create the FileDialogResult.
+     */
+    constructor($$source = {}) {
+        if (!("filePath" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["filePath"] = "";
+        }
+        if (!("success" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["success"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FileDialogResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FileDialogResult}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FileDialogResult(/** @type {Partial<FileDialogResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * 校验过程可能返回类型
+ */
+export class HeaderError {
+    /**
+     * Creates a new HeaderError instance.
+     * @param {Partial<HeaderError>} [$$source = {}] - The source object to create the HeaderError.
+     */
+    constructor($$source = {}) {
+        if (!("missing" in $$source)) {
+            /**
+             * 缺失字段
+             * @member
+             * @type {string[]}
+             */
+            this["missing"] = [];
+        }
+        if (!("extra" in $$source)) {
+            /**
+             * 多余字段
+             * @member
+             * @type {string[]}
+             */
+            this["extra"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new HeaderError instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {HeaderError}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("missing" in $$parsedSource) {
+            $$parsedSource["missing"] = $$createField0_0($$parsedSource["missing"]);
+        }
+        if ("extra" in $$parsedSource) {
+            $$parsedSource["extra"] = $$createField1_0($$parsedSource["extra"]);
+        }
+        return new HeaderError(/** @type {Partial<HeaderError>} */($$parsedSource));
+    }
+}
+
+/**
+ * ImportIssue 导入过程中发现的单行问题
+ */
+export class ImportIssue {
+    /**
+     * Creates a new ImportIssue instance.
+     * @param {Partial<ImportIssue>} [$$source = {}] - The source object to create the ImportIssue.
+     */
+    constructor($$source = {}) {
+        if (!("row" in $$source)) {
+            /**
+             * 文件中的行号，从1开始，标题行为第1行；0表示与具体行无关
+             * @member
+             * @type {number}
+             */
+            this["row"] = 0;
+        }
+        if (!("kind" in $$source)) {
+            /**
+             * 问题类型
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("message" in $$source)) {
+            /**
+             * 问题描述
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportIssue instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ImportIssue}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportIssue(/** @type {Partial<ImportIssue>} */($$parsedSource));
+    }
+}
+
+/**
+ * ImportOptions 导入选项
+ */
+export class ImportOptions {
+    /**
+     * Creates a new ImportOptions instance.
+     * @param {Partial<ImportOptions>} [$$source = {}] - The source object to create the ImportOptions.
+     */
+    constructor($$source = {}) {
+        if (!("encoding" in $$source)) {
+            /**
+             * 文件编码，auto 为自动检测（仅CSV）
+             * @member
+             * @type {string}
+             */
+            this["encoding"] = "";
+        }
+        if (!("optionSeparator" in $$source)) {
+            /**
+             * 选项分隔符，auto 为自动检测
+             * @member
+             * @type {string}
+             */
+            this["optionSeparator"] = "";
+        }
+        if (!("answerSeparator" in $$source)) {
+            /**
+             * 答案分隔符，auto 为自动检测
+             * @member
+             * @type {string}
+             */
+            this["answerSeparator"] = "";
+        }
+        if (!("sheetName" in $$source)) {
+            /**
+             * 工作表名称，为空时读取第一个工作表（仅Excel）
+             * @member
+             * @type {string}
+             */
+            this["sheetName"] = "";
+        }
+        if (!("mapping" in $$source)) {
+            /**
+             * 列映射预设名称，为空时使用默认映射
+             * @member
+             * @type {string}
+             */
+            this["mapping"] = "";
+        }
+        if (!("lenient" in $$source)) {
+            /**
+             * 宽松模式：跳过有问题的行并返回诊断报告
+             * @member
+             * @type {boolean}
+             */
+            this["lenient"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportOptions instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ImportOptions}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportOptions(/** @type {Partial<ImportOptions>} */($$parsedSource));
+    }
+}
+
+/**
+ * ImportReport 导入诊断报告
+ */
+export class ImportReport {
+    /**
+     * Creates a new ImportReport instance.
+     * @param {Partial<ImportReport>} [$$source = {}] - The source object to create the ImportReport.
+     */
+    constructor($$source = {}) {
+        if (!("totalRows" in $$source)) {
+            /**
+             * 数据行总数
+             * @member
+             * @type {number}
+             */
+            this["totalRows"] = 0;
+        }
+        if (!("importedRows" in $$source)) {
+            /**
+             * 成功导入的行数
+             * @member
+             * @type {number}
+             */
+            this["importedRows"] = 0;
+        }
+        if (!("skippedRows" in $$source)) {
+            /**
+             * 跳过的行数
+             * @member
+             * @type {number}
+             */
+            this["skippedRows"] = 0;
+        }
+        if (!("header" in $$source)) {
+            /**
+             * 标题行校验结果
+             * @member
+             * @type {HeaderError}
+             */
+            this["header"] = (new HeaderError());
+        }
+        if (!("issues" in $$source)) {
+            /**
+             * 逐行问题列表
+             * @member
+             * @type {ImportIssue[]}
+             */
+            this["issues"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ImportReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType11;
+        const $$createField4_0 = $$createType13;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("header" in $$parsedSource) {
+            $$parsedSource["header"] = $$createField3_0($$parsedSource["header"]);
+        }
+        if ("issues" in $$parsedSource) {
+            $$parsedSource["issues"] = $$createField4_0($$parsedSource["issues"]);
+        }
+        return new ImportReport(/** @type {Partial<ImportReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * ImportResult 导入结果
+ */
+export class ImportResult {
+    /**
+     * Creates a new ImportResult instance.
+     * @param {Partial<ImportResult>} [$$source = {}] - The source object to create the ImportResult.
+     */
+    constructor($$source = {}) {
+        if (!("answers" in $$source)) {
+            /**
+             * @member
+             * @type {AnswerItem[]}
+             */
+            this["answers"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 宽松模式下的诊断报告
+             * @member
+             * @type {ImportReport | null | undefined}
+             */
+            this["report"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 实际使用的编码和分隔符
+             * @member
+             * @type {DetectedSettings | null | undefined}
+             */
+            this["settings"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 题库文件中记录的题库名称
+             * @member
+             * @type {string | undefined}
+             */
+            this["name"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ImportResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType15;
+        const $$createField1_0 = $$createType17;
+        const $$createField2_0 = $$createType19;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("answers" in $$parsedSource) {
+            $$parsedSource["answers"] = $$createField0_0($$parsedSource["answers"]);
+        }
+        if ("report" in $$parsedSource) {
+            $$parsedSource["report"] = $$createField1_0($$parsedSource["report"]);
+        }
+        if ("settings" in $$parsedSource) {
+            $$parsedSource["settings"] = $$createField2_0($$parsedSource["settings"]);
+        }
+        return new ImportResult(/** @type {Partial<ImportResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * OCRConfig OCR配置
+ */
+export class OCRConfig {
+    /**
+     * Creates a new OCRConfig instance.
+     * @param {Partial<OCRConfig>} [$$source = {}] - The source object to create the OCRConfig.
+     */
+    constructor($$source = {}) {
+        if (!("mode" in $$source)) {
+            /**
+             * "local"、"online" 或 "tesseract"，见 OCREngine
+             * @member
+             * @type {string}
+             */
+            this["mode"] = "";
+        }
+        if (!("url" in $$source)) {
+            /**
+             * 在线OCR URL
+             * @member
+             * @type {string}
+             */
+            this["url"] = "";
+        }
+        if (!("apiKey" in $$source)) {
+            /**
+             * API密钥
+             * @member
+             * @type {string}
+             */
+            this["apiKey"] = "";
+        }
+        if (!("status" in $$source)) {
+            /**
+             * 连接状态
+             * @member
+             * @type {string}
+             */
+            this["status"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * tesseract 模式的命令行配置
+             * @member
+             * @type {TesseractConfig | undefined}
+             */
+            this["tesseract"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 识别前使用的预处理方案名称，为空时不处理
+             * @member
+             * @type {string | undefined}
+             */
+            this["preprocess"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 自定义预处理方案，同名时覆盖内置方案
+             * @member
+             * @type {{ [_: string]: PreprocessProfile } | undefined}
+             */
+            this["profiles"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 在HTTP响应中返回预处理后的图片
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["debug"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 丢弃整体置信度低于此值的识别结果，默认0.3
+             * @member
+             * @type {number | undefined}
+             */
+            this["minConfidence"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 置信度低于此值的字符在搜索时作为通配符，默认0.6
+             * @member
+             * @type {number | undefined}
+             */
+            this["wildcardConfidence"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OCRConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {OCRConfig}
+     */
+    static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType20;
+        const $$createField6_0 = $$createType22;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tesseract" in $$parsedSource) {
+            $$parsedSource["tesseract"] = $$createField4_0($$parsedSource["tesseract"]);
+        }
+        if ("profiles" in $$parsedSource) {
+            $$parsedSource["profiles"] = $$createField6_0($$parsedSource["profiles"]);
+        }
+        return new OCRConfig(/** @type {Partial<OCRConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * ParsedQuestion 从OCR结果中解析出的结构化题目
+ */
+export class ParsedQuestion {
+    /**
+     * Creates a new ParsedQuestion instance.
+     * @param {Partial<ParsedQuestion>} [$$source = {}] - The source object to create the ParsedQuestion.
+     */
+    constructor($$source = {}) {
+        if (!("number" in $$source)) {
+            /**
+             * 题号
+             * @member
+             * @type {string}
+             */
+            this["number"] = "";
+        }
+        if (!("type" in $$source)) {
+            /**
+             * 题型提示：单选题、多选题或判断题
+             * @member
+             * @type {string}
+             */
+            this["type"] = "";
+        }
+        if (!("stem" in $$source)) {
+            /**
+             * 题干
+             * @member
+             * @type {string}
+             */
+            this["stem"] = "";
+        }
+        if (!("options" in $$source)) {
+            /**
+             * 选项文本，不含 A. 等标签
+             * @member
+             * @type {string[]}
+             */
+            this["options"] = [];
+        }
+        if (!("text" in $$source)) {
+            /**
+             * 按阅读顺序重建的完整文本，各行以换行分隔
+             * @member
+             * @type {string}
+             */
+            this["text"] = "";
+        }
+        if (!("lines" in $$source)) {
+            /**
+             * 按阅读顺序重建的文本行
+             * @member
+             * @type {string[]}
+             */
+            this["lines"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 识别文字的平均置信度，低置信度字符已替换为 □
+             * @member
+             * @type {number | undefined}
+             */
+            this["confidence"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ParsedQuestion instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ParsedQuestion}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType0;
+        const $$createField5_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("options" in $$parsedSource) {
+            $$parsedSource["options"] = $$createField3_0($$parsedSource["options"]);
+        }
+        if ("lines" in $$parsedSource) {
+            $$parsedSource["lines"] = $$createField5_0($$parsedSource["lines"]);
+        }
+        return new ParsedQuestion(/** @type {Partial<ParsedQuestion>} */($$parsedSource));
+    }
+}
+
+/**
+ * PreprocessProfile OCR前的图片预处理方案
+ * 启用任一处理时图片都会先转为灰度
+ */
+export class PreprocessProfile {
+    /**
+     * Creates a new PreprocessProfile instance.
+     * @param {Partial<PreprocessProfile>} [$$source = {}] - The source object to create the PreprocessProfile.
+     */
+    constructor($$source = {}) {
+        if (!("grayscale" in $$source)) {
+            /**
+             * 转为灰度
+             * @member
+             * @type {boolean}
+             */
+            this["grayscale"] = false;
+        }
+        if (!("contrast" in $$source)) {
+            /**
+             * 对比度拉伸
+             * @member
+             * @type {boolean}
+             */
+            this["contrast"] = false;
+        }
+        if (!("binarize" in $$source)) {
+            /**
+             * 自适应二值化
+             * @member
+             * @type {boolean}
+             */
+            this["binarize"] = false;
+        }
+        if (!("invert" in $$source)) {
+            /**
+             * 反色方式："auto"、"always" 或空
+             * @member
+             * @type {string}
+             */
+            this["invert"] = "";
+        }
+        if (!("minHeight" in $$source)) {
+            /**
+             * 图片高度小于此值时按整数倍放大，0 表示不放大
+             * @member
+             * @type {number}
+             */
+            this["minHeight"] = 0;
+        }
+        if (!("deskew" in $$source)) {
+            /**
+             * 纠正倾斜
+             * @member
+             * @type {boolean}
+             */
+            this["deskew"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PreprocessProfile instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PreprocessProfile}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PreprocessProfile(/** @type {Partial<PreprocessProfile>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScanImportRequest 从扫描件导入题目的请求
+ * 不接受 pdftoppm 路径，渲染PDF时只从 PATH 中查找，以免HTTP接口的调用方借此执行任意程序
+ */
+export class ScanImportRequest {
+    /**
+     * Creates a new ScanImportRequest instance.
+     * @param {Partial<ScanImportRequest>} [$$source = {}] - The source object to create the ScanImportRequest.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * 图片目录、单张图片或PDF文件
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("config" in $$source)) {
+            /**
+             * OCR配置
+             * @member
+             * @type {OCRConfig}
+             */
+            this["config"] = (new OCRConfig());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * PDF渲染分辨率，默认200
+             * @member
+             * @type {number | undefined}
+             */
+            this["dpi"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScanImportRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScanImportRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("config" in $$parsedSource) {
+            $$parsedSource["config"] = $$createField1_0($$parsedSource["config"]);
+        }
+        return new ScanImportRequest(/** @type {Partial<ScanImportRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScanImportResult 扫描件导入结果，题目为待审核的草稿，确认后通过 ImportBank 保存为题库
+ * 诊断报告中的行号为题目在草稿中的序号
+ */
+export class ScanImportResult {
+    /**
+     * Creates a new ScanImportResult instance.
+     * @param {Partial<ScanImportResult>} [$$source = {}] - The source object to create the ScanImportResult.
+     */
+    constructor($$source = {}) {
+        if (!("answers" in $$source)) {
+            /**
+             * @member
+             * @type {AnswerItem[]}
+             */
+            this["answers"] = [];
+        }
+        if (!("report" in $$source)) {
+            /**
+             * @member
+             * @type {ImportReport | null}
+             */
+            this["report"] = null;
+        }
+        if (!("pages" in $$source)) {
+            /**
+             * @member
+             * @type {ScanPage[]}
+             */
+            this["pages"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScanImportResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScanImportResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType15;
+        const $$createField1_0 = $$createType17;
+        const $$createField2_0 = $$createType24;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("answers" in $$parsedSource) {
+            $$parsedSource["answers"] = $$createField0_0($$parsedSource["answers"]);
+        }
+        if ("report" in $$parsedSource) {
+            $$parsedSource["report"] = $$createField1_0($$parsedSource["report"]);
+        }
+        if ("pages" in $$parsedSource) {
+            $$parsedSource["pages"] = $$createField2_0($$parsedSource["pages"]);
+        }
+        return new ScanImportResult(/** @type {Partial<ScanImportResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScanPage 一页扫描件的识别情况
+ */
+export class ScanPage {
+    /**
+     * Creates a new ScanPage instance.
+     * @param {Partial<ScanPage>} [$$source = {}] - The source object to create the ScanPage.
+     */
+    constructor($$source = {}) {
+        if (!("source" in $$source)) {
+            /**
+             * 图片文件名或 PDF 页码
+             * @member
+             * @type {string}
+             */
+            this["source"] = "";
+        }
+        if (!("lines" in $$source)) {
+            /**
+             * 识别出的文本行数
+             * @member
+             * @type {number}
+             */
+            this["lines"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 平均置信度
+             * @member
+             * @type {number | undefined}
+             */
+            this["confidence"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 识别失败的原因
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScanPage instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScanPage}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ScanPage(/** @type {Partial<ScanPage>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScreenshotArea 截图区域
+ */
+export class ScreenshotArea {
+    /**
+     * Creates a new ScreenshotArea instance.
+     * @param {Partial<ScreenshotArea>} [$$source = {}] - The source object to create the ScreenshotArea.
+     */
+    constructor($$source = {}) {
+        if (!("x" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["x"] = 0;
+        }
+        if (!("y" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["y"] = 0;
+        }
+        if (!("width" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["width"] = 0;
+        }
+        if (!("height" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["height"] = 0;
+        }
+        if (!("image" in $$source)) {
+            /**
+             * base64编码的图片或 data URL，支持PNG、JPEG和WebP
+             * @member
+             * @type {string}
+             */
+            this["image"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 缓存中的截图标识，设置后不需要传递图片数据
+             * @member
+             * @type {string | undefined}
+             */
+            this["captureId"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 所在显示器标识，为空时表示主显示器
+             * @member
+             * @type {string | undefined}
+             */
+            this["displayId"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScreenshotArea instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScreenshotArea}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ScreenshotArea(/** @type {Partial<ScreenshotArea>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScreenshotBackendStatus 截图后端检测结果
+ */
+export class ScreenshotBackendStatus {
+    /**
+     * Creates a new ScreenshotBackendStatus instance.
+     * @param {Partial<ScreenshotBackendStatus>} [$$source = {}] - The source object to create the ScreenshotBackendStatus.
+     */
+    constructor($$source = {}) {
+        if (!("name" in $$source)) {
+            /**
+             * 后端名称
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 可执行文件路径
+             * @member
+             * @type {string | undefined}
+             */
+            this["path"] = undefined;
+        }
+        if (!("available" in $$source)) {
+            /**
+             * 是否可用
+             * @member
+             * @type {boolean}
+             */
+            this["available"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 不可用的原因或使用提示
+             * @member
+             * @type {string | undefined}
+             */
+            this["message"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScreenshotBackendStatus instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScreenshotBackendStatus}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ScreenshotBackendStatus(/** @type {Partial<ScreenshotBackendStatus>} */($$parsedSource));
+    }
+}
+
+/**
+ * ScreenshotDiagnostics 截图环境诊断信息
+ */
+export class ScreenshotDiagnostics {
+    /**
+     * Creates a new ScreenshotDiagnostics instance.
+     * @param {Partial<ScreenshotDiagnostics>} [$$source = {}] - The source object to create the ScreenshotDiagnostics.
+     */
+    constructor($$source = {}) {
+        if (!("os" in $$source)) {
+            /**
+             * 操作系统
+             * @member
+             * @type {string}
+             */
+            this["os"] = "";
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 图形会话类型：wayland 或 x11
+             * @member
+             * @type {string | undefined}
+             */
+            this["session"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 设置的截图文件
+             * @member
+             * @type {string | undefined}
+             */
+            this["file"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 最近一次截图成功使用的后端
+             * @member
+             * @type {string | undefined}
+             */
+            this["lastBackend"] = undefined;
+        }
+        if (!("backends" in $$source)) {
+            /**
+             * 按尝试顺序排列的截图后端
+             * @member
+             * @type {ScreenshotBackendStatus[]}
+             */
+            this["backends"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ScreenshotDiagnostics instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ScreenshotDiagnostics}
+     */
+    static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType26;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("backends" in $$parsedSource) {
+            $$parsedSource["backends"] = $$createField4_0($$parsedSource["backends"]);
+        }
+        return new ScreenshotDiagnostics(/** @type {Partial<ScreenshotDiagnostics>} */($$parsedSource));
+    }
+}
+
+/**
+ * SearchFilters 搜索筛选参数
+ */
+export class SearchFilters {
+    /**
+     * Creates a new SearchFilters instance.
+     * @param {Partial<SearchFilters>} [$$source = {}] - The source object to create the SearchFilters.
+     */
+    constructor($$source = {}) {
+        if (!("accuracyFilters" in $$source)) {
+            /**
+             * @member
+             * @type {AccuracyFilters}
+             */
+            this["accuracyFilters"] = (new AccuracyFilters());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 标签，题目包含任一标签即可
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["tags"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 难度，题目难度为其中之一即可
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["difficulties"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 来源关键字
+             * @member
+             * @type {string | undefined}
+             */
+            this["source"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 排序方式：overlap（默认，重合度）或 bm25
+             * @member
+             * @type {string | undefined}
+             */
+            this["ranking"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchFilters instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {SearchFilters}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType27;
+        const $$createField1_0 = $$createType0;
+        const $$createField2_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("accuracyFilters" in $$parsedSource) {
+            $$parsedSource["accuracyFilters"] = $$createField0_0($$parsedSource["accuracyFilters"]);
+        }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField1_0($$parsedSource["tags"]);
+        }
+        if ("difficulties" in $$parsedSource) {
+            $$parsedSource["difficulties"] = $$createField2_0($$parsedSource["difficulties"]);
+        }
+        return new SearchFilters(/** @type {Partial<SearchFilters>} */($$parsedSource));
+    }
+}
+
+/**
+ * SearchResult 搜索结果
+ */
+export class SearchResult {
+    /**
+     * Creates a new SearchResult instance.
+     * @param {Partial<SearchResult>} [$$source = {}] - The source object to create the SearchResult.
+     */
+    constructor($$source = {}) {
+        if (!("item" in $$source)) {
+            /**
+             * @member
+             * @type {AnswerItem}
+             */
+            this["item"] = (new AnswerItem());
+        }
+        if (!("score" in $$source)) {
+            /**
+             * 匹配度
+             * @member
+             * @type {number}
+             */
+            this["score"] = 0;
+        }
+        if (!("matched" in $$source)) {
+            /**
+             * 匹配的文本
+             * @member
+             * @type {string}
+             */
+            this["matched"] = "";
+        }
+        if (!("questionMatches" in $$source)) {
+            /**
+             * 题目匹配位置
+             * @member
+             * @type {number[]}
+             */
+            this["questionMatches"] = [];
+        }
+        if (!("optionMatches" in $$source)) {
+            /**
+             * 选项匹配位置，key为选项文本
+             * @member
+             * @type {{ [_: string]: number[] }}
+             */
+            this["optionMatches"] = {};
+        }
+        if (!("answerMatches" in $$source)) {
+            /**
+             * 答案匹配位置（不使用）
+             * @member
+             * @type {number[]}
+             */
+            this["answerMatches"] = [];
+        }
+        if (!("explanationMatches" in $$source)) {
+            /**
+             * 解析匹配位置
+             * @member
+             * @type {number[]}
+             */
+            this["explanationMatches"] = [];
+        }
+        if (!("bankId" in $$source)) {
+            /**
+             * 所属题库ID
+             * @member
+             * @type {string}
+             */
+            this["bankId"] = "";
+        }
+        if (!("bankName" in $$source)) {
+            /**
+             * 所属题库名称
+             * @member
+             * @type {string}
+             */
+            this["bankName"] = "";
+        }
+        if (!("ocrConfidence" in $$source)) {
+            /**
+             * 查询文本的OCR平均置信度，0表示未知
+             * @member
+             * @type {number}
+             */
+            this["ocrConfidence"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {SearchResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType14;
+        const $$createField3_0 = $$createType28;
+        const $$createField4_0 = $$createType29;
+        const $$createField5_0 = $$createType28;
+        const $$createField6_0 = $$createType28;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("item" in $$parsedSource) {
+            $$parsedSource["item"] = $$createField0_0($$parsedSource["item"]);
+        }
+        if ("questionMatches" in $$parsedSource) {
+            $$parsedSource["questionMatches"] = $$createField3_0($$parsedSource["questionMatches"]);
+        }
+        if ("optionMatches" in $$parsedSource) {
+            $$parsedSource["optionMatches"] = $$createField4_0($$parsedSource["optionMatches"]);
+        }
+        if ("answerMatches" in $$parsedSource) {
+            $$parsedSource["answerMatches"] = $$createField5_0($$parsedSource["answerMatches"]);
+        }
+        if ("explanationMatches" in $$parsedSource) {
+            $$parsedSource["explanationMatches"] = $$createField6_0($$parsedSource["explanationMatches"]);
         }
         return new SearchResult(/** @type {Partial<SearchResult>} */($$parsedSource));
     }
 }
 
+/**
+ * StageTimings 各阶段耗时，单位为毫秒
+ */
+export class StageTimings {
+    /**
+     * Creates a new StageTimings instance.
+     * @param {Partial<StageTimings>} [$$source = {}] - The source object to create the StageTimings.
+     */
+    constructor($$source = {}) {
+        if (!("screenshotMs" in $$source)) {
+            /**
+             * 截图
+             * @member
+             * @type {number}
+             */
+            this["screenshotMs"] = 0;
+        }
+        if (!("cropMs" in $$source)) {
+            /**
+             * 裁剪
+             * @member
+             * @type {number}
+             */
+            this["cropMs"] = 0;
+        }
+        if (!("preprocessMs" in $$source)) {
+            /**
+             * 图片预处理
+             * @member
+             * @type {number}
+             */
+            this["preprocessMs"] = 0;
+        }
+        if (!("ocrMs" in $$source)) {
+            /**
+             * OCR识别
+             * @member
+             * @type {number}
+             */
+            this["ocrMs"] = 0;
+        }
+        if (!("parseMs" in $$source)) {
+            /**
+             * 解析题目结构
+             * @member
+             * @type {number}
+             */
+            this["parseMs"] = 0;
+        }
+        if (!("searchMs" in $$source)) {
+            /**
+             * 搜索题库
+             * @member
+             * @type {number}
+             */
+            this["searchMs"] = 0;
+        }
+        if (!("totalMs" in $$source)) {
+            /**
+             * 总耗时
+             * @member
+             * @type {number}
+             */
+            this["totalMs"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new StageTimings instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {StageTimings}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new StageTimings(/** @type {Partial<StageTimings>} */($$parsedSource));
+    }
+}
+
+/**
+ * TesseractConfig tesseract 命令行配置
+ */
+export class TesseractConfig {
+    /**
+     * Creates a new TesseractConfig instance.
+     * @param {Partial<TesseractConfig>} [$$source = {}] - The source object to create the TesseractConfig.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * 识别语言，多个语言以 + 连接，默认 chi_sim+eng
+             * @member
+             * @type {string | undefined}
+             */
+            this["languages"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 页面分割模式，0 表示使用 tesseract 的默认值
+             * @member
+             * @type {number | undefined}
+             */
+            this["psm"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TesseractConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TesseractConfig}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TesseractConfig(/** @type {Partial<TesseractConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * WatchRequest 监视截图区域的请求
+ */
+export class WatchRequest {
+    /**
+     * Creates a new WatchRequest instance.
+     * @param {Partial<WatchRequest>} [$$source = {}] - The source object to create the WatchRequest.
+     */
+    constructor($$source = {}) {
+        if (/** @type {any} */(false)) {
+            /**
+             * 已保存的截图区域名称
+             * @member
+             * @type {string | undefined}
+             */
+            this["region"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 未指定区域名称时使用的区域
+             * @member
+             * @type {CaptureRegion | null | undefined}
+             */
+            this["area"] = undefined;
+        }
+        if (!("config" in $$source)) {
+            /**
+             * OCR配置
+             * @member
+             * @type {OCRConfig}
+             */
+            this["config"] = (new OCRConfig());
+        }
+        if (!("filters" in $$source)) {
+            /**
+             * 搜索筛选条件
+             * @member
+             * @type {SearchFilters}
+             */
+            this["filters"] = (new SearchFilters());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 截图间隔，默认1000毫秒，最小200毫秒
+             * @member
+             * @type {number | undefined}
+             */
+            this["intervalMs"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 差异哈希的汉明距离阈值，默认6
+             * @member
+             * @type {number | undefined}
+             */
+            this["hashDistance"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * 变化像素比例阈值，默认0.001
+             * @member
+             * @type {number | undefined}
+             */
+            this["pixelRatio"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WatchRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {WatchRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType3;
+        const $$createField2_0 = $$createType4;
+        const $$createField3_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("area" in $$parsedSource) {
+            $$parsedSource["area"] = $$createField1_0($$parsedSource["area"]);
+        }
+        if ("config" in $$parsedSource) {
+            $$parsedSource["config"] = $$createField2_0($$parsedSource["config"]);
+        }
+        if ("filters" in $$parsedSource) {
+            $$parsedSource["filters"] = $$createField3_0($$parsedSource["filters"]);
+        }
+        return new WatchRequest(/** @type {Partial<WatchRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * WatchStatus 监视状态
+ */
+export class WatchStatus {
+    /**
+     * Creates a new WatchStatus instance.
+     * @param {Partial<WatchStatus>} [$$source = {}] - The source object to create the WatchStatus.
+     */
+    constructor($$source = {}) {
+        if (!("running" in $$source)) {
+            /**
+             * 是否正在监视
+             * @member
+             * @type {boolean}
+             */
+            this["running"] = false;
+        }
+        if (!("session" in $$source)) {
+            /**
+             * 监视序号，每次开始监视时递增
+             * @member
+             * @type {number}
+             */
+            this["session"] = 0;
+        }
+        if (!("region" in $$source)) {
+            /**
+             * 监视的截图区域
+             * @member
+             * @type {CaptureRegion}
+             */
+            this["region"] = (new CaptureRegion());
+        }
+        if (!("intervalMs" in $$source)) {
+            /**
+             * 截图间隔
+             * @member
+             * @type {number}
+             */
+            this["intervalMs"] = 0;
+        }
+        if (!("frames" in $$source)) {
+            /**
+             * 已截图次数
+             * @member
+             * @type {number}
+             */
+            this["frames"] = 0;
+        }
+        if (!("changes" in $$source)) {
+            /**
+             * 检测到内容变化并识别的次数
+             * @member
+             * @type {number}
+             */
+            this["changes"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["lastError"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WatchStatus instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {WatchStatus}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("region" in $$parsedSource) {
+            $$parsedSource["region"] = $$createField2_0($$parsedSource["region"]);
+        }
+        return new WatchStatus(/** @type {Partial<WatchStatus>} */($$parsedSource));
+    }
+}
+
 // Private type creation functions
 const $$createType0 = $Create.Array($Create.Any);
-const $$createType1 = AnswerItem.createFrom;
-const $$createType2 = $Create.Array($Create.Any);
-const $$createType3 = $Create.Map($Create.Any, $$createType2);
+const $$createType1 = $Create.Map($Create.Any, $Create.Any);
+const $$createType2 = CaptureRegion.createFrom;
+const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = OCRConfig.createFrom;
+const $$createType5 = SearchFilters.createFrom;
+const $$createType6 = ParsedQuestion.createFrom;
+const $$createType7 = SearchResult.createFrom;
+const $$createType8 = $Create.Array($$createType7);
+const $$createType9 = StageTimings.createFrom;
+const $$createType10 = $Create.Map($Create.Any, $$createType0);
+const $$createType11 = HeaderError.createFrom;
+const $$createType12 = ImportIssue.createFrom;
+const $$createType13 = $Create.Array($$createType12);
+const $$createType14 = AnswerItem.createFrom;
+const $$createType15 = $Create.Array($$createType14);
+const $$createType16 = ImportReport.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = DetectedSettings.createFrom;
+const $$createType19 = $Create.Nullable($$createType18);
+const $$createType20 = TesseractConfig.createFrom;
+const $$createType21 = PreprocessProfile.createFrom;
+const $$createType22 = $Create.Map($Create.Any, $$createType21);
+const $$createType23 = ScanPage.createFrom;
+const $$createType24 = $Create.Array($$createType23);
+const $$createType25 = ScreenshotBackendStatus.createFrom;
+const $$createType26 = $Create.Array($$createType25);
+const $$createType27 = AccuracyFilters.createFrom;
+const $$createType28 = $Create.Array($Create.Any);
+const $$createType29 = $Create.Map($Create.Any, $$createType28);
@@ -6,9 +6,10 @@ const API_BASE_URL = 'http://localhost:8088'
  * 搜索答案
  * @param {string} query - 搜索查询
  * @param {Object} filters - 过滤条件
+ * @param {string} mode - 搜索模式：text 按整段文本，options 按题干和选项组合
  * @returns {Promise<Array>} 搜索结果
  */
-export async function searchAnswers(query, filters = {}) {
+export async function searchAnswers(query, filters = {}, mode = 'text', ocrConfidence = 0) {
   try {
     const response = await fetch(`${API_BASE_URL}/api/search`, {
       method: 'POST',
@@ -17,7 +18,9 @@ export async function searchAnswers(query, filters = {}) {
       },
       body: JSON.stringify({
         query,
-        filters
+        filters,
+        mode,
+        ocrConfidence
       })
     })
 
@@ -78,6 +81,532 @@ export async function parseCSVFile(filePath, encoding, optionSeparator, answerSe
   }
 }
 
+/**
+ * 宽松模式解析CSV文件，跳过有问题的行并返回逐行诊断报告
+ * @param {string} filePath - 文件路径
+ * @param {string} encoding - 文件编码
+ * @param {string} optionSeparator - 选项分隔符
+ * @param {string} answerSeparator - 答案分隔符
+ * @returns {Promise<{results: Array, report: Object}>} 解析结果和诊断报告
+ */
+export async function parseCSVFileWithReport(filePath, encoding, optionSeparator, answerSeparator) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/parse-csv`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        filePath,
+        encoding,
+        optionSeparator,
+        answerSeparator,
+        lenient: true
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || 'CSV解析失败')
+    }
+
+    return {
+      results: data.results || [],
+      report: data.report
+    }
+  } catch (error) {
+    console.error('CSV解析失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 自动检测文件的编码、字段分隔符以及选项和答案分隔符
+ * @param {string} filePath - 文件路径
+ * @returns {Promise<Object>} 检测结果
+ */
+export async function detectSettings(filePath) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/detect-settings`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        filePath
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '检测导入设置失败')
+    }
+
+    return data.settings
+  } catch (error) {
+    console.error('检测导入设置失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 按扩展名导入CSV、Excel或JSON、JSONL、YAML题库文件，支持列映射预设
+ * @param {string} filePath - 文件路径
+ * @param {Object} options - 导入选项（encoding、optionSeparator、answerSeparator、sheetName、mapping、lenient）
+ * @returns {Promise<Object>} 导入结果，包含 answers、report、settings 和题库文件中记录的 name
+ */
+export async function importFile(filePath, options = {}) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/import-file`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        filePath,
+        options
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '文件导入失败')
+    }
+
+    return {
+      answers: data.results || [],
+      report: data.report,
+      settings: data.settings,
+      name: data.name
+    }
+  } catch (error) {
+    console.error('文件导入失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 识别扫描件中的题目，生成待审核的题目草稿，确认后通过 importBank 保存为题库
+ * @param {Object} request - 请求参数（path 为图片目录、单张图片或PDF文件，config 为OCR配置，dpi 可选）
+ * @returns {Promise<Object>} 识别结果，包含 answers、report 和 pages
+ */
+export async function importScans(request) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/import-scans`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(request)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+
+    if (!data.success) {
+      throw new Error(data.message || '扫描件识别失败')
+    }
+
+    return {
+      answers: data.results || [],
+      report: data.report,
+      pages: data.pages || []
+    }
+  } catch (error) {
+    console.error('扫描件识别失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取所有列映射预设
+ * @returns {Promise<Array>} 列映射预设列表
+ */
+export async function listColumnMappings() {
+  return requestColumnMappings('GET')
+}
+
+/**
+ * 保存列映射预设，同名预设会被覆盖
+ * @param {Object} mapping - 列映射预设（name、aliases、optionColumns）
+ * @returns {Promise<Array>} 保存后的列映射预设列表
+ */
+export async function saveColumnMapping(mapping) {
+  return requestColumnMappings('POST', mapping)
+}
+
+/**
+ * 删除列映射预设
+ * @param {string} name - 预设名称
+ * @returns {Promise<Array>} 删除后的列映射预设列表
+ */
+export async function deleteColumnMapping(name) {
+  return requestColumnMappings('DELETE', undefined, `?name=${encodeURIComponent(name)}`)
+}
+
+async function requestColumnMappings(method, body, query = '') {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/column-mappings${query}`, {
+      method,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: body === undefined ? undefined : JSON.stringify(body)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '列映射预设操作失败')
+    }
+
+    return data.mappings || []
+  } catch (error) {
+    console.error('列映射预设操作失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取用户分词词典
+ * @returns {Promise<Array>} 词语列表，每项可带空格分隔的词频
+ */
+export async function getUserDictionary() {
+  return requestUserDictionary('GET')
+}
+
+/**
+ * 保存用户分词词典，保存后立即用于搜索
+ * @param {Array<string>} words - 词语列表，每项可带空格分隔的词频
+ * @returns {Promise<Array>} 保存后的词语列表
+ */
+export async function saveUserDictionary(words) {
+  return requestUserDictionary('POST', { words })
+}
+
+async function requestUserDictionary(method, body) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/user-dictionary`, {
+      method,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: body === undefined ? undefined : JSON.stringify(body)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '用户词典操作失败')
+    }
+
+    return data.words || []
+  } catch (error) {
+    console.error('用户词典操作失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取用户自定义的OCR易混淆字符组
+ * @returns {Promise<Array>} 字符组列表，每项可带空格分隔的替换代价
+ */
+export async function getConfusables() {
+  return requestConfusables('GET')
+}
+
+/**
+ * 保存用户自定义的OCR易混淆字符组，保存后立即用于搜索
+ * @param {Array<string>} groups - 字符组列表，如 "己已巳"、"未末 0.2"
+ * @returns {Promise<Array>} 保存后的字符组列表
+ */
+export async function saveConfusables(groups) {
+  return requestConfusables('POST', { groups })
+}
+
+async function requestConfusables(method, body) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/confusables`, {
+      method,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: body === undefined ? undefined : JSON.stringify(body)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '易混淆字符操作失败')
+    }
+
+    return data.groups || []
+  } catch (error) {
+    console.error('易混淆字符操作失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取所有已保存的截图区域
+ * @returns {Promise<Array>} 截图区域列表
+ */
+export async function listCaptureRegions() {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/capture-regions`)
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '获取截图区域失败')
+    }
+
+    return data.regions || []
+  } catch (error) {
+    console.error('获取截图区域失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 保存截图区域，同名区域会被覆盖
+ * @param {Object} region - 截图区域，包含 name、displayId、x、y、width、height
+ * @returns {Promise<Array>} 保存后的截图区域列表
+ */
+export async function saveCaptureRegion(region) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/capture-regions`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(region)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '保存截图区域失败')
+    }
+
+    return data.regions || []
+  } catch (error) {
+    console.error('保存截图区域失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 删除截图区域
+ * @param {string} name - 区域名称
+ * @returns {Promise<Array>} 删除后的截图区域列表
+ */
+export async function deleteCaptureRegion(name) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/capture-regions?name=${encodeURIComponent(name)}`, {
+      method: 'DELETE'
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '删除截图区域失败')
+    }
+
+    return data.regions || []
+  } catch (error) {
+    console.error('删除截图区域失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 截图、识别并搜索，一次请求完成
+ * @param {Object} request - 包含 region（区域名称）或 area（区域）、config、filters、hideWindow
+ * @returns {Promise<Object>} 识别文字、题目结构、搜索结果和各阶段耗时
+ */
+export async function captureAndSearch(request) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/capture-search`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(request)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '截图搜索失败')
+    }
+
+    return data.result
+  } catch (error) {
+    console.error('截图搜索失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 开始监视截图区域，区域内容变化后自动识别并搜索
+ * @param {Object} request - 监视请求，包含 region 或 area、config、filters、intervalMs
+ * @returns {Promise<Object>} 监视状态
+ */
+export async function startWatch(request) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/watch`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify(request)
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '开始监视失败')
+    }
+
+    return data.status
+  } catch (error) {
+    console.error('开始监视失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 停止监视截图区域
+ * @returns {Promise<Object>} 监视状态
+ */
+export async function stopWatch() {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/watch`, {
+      method: 'DELETE'
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    return data.status
+  } catch (error) {
+    console.error('停止监视失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 订阅监视事件
+ * @param {Function} onEvent - 事件回调，参数包含 type（result、error、stopped）、frame、result、message
+ * @returns {Function} 取消订阅函数
+ */
+export function subscribeWatch(onEvent) {
+  const source = new EventSource(`${API_BASE_URL}/api/watch/stream`)
+  for (const type of ['result', 'error', 'stopped']) {
+    source.addEventListener(type, (event) => {
+      try {
+        onEvent(JSON.parse(event.data))
+      } catch (error) {
+        console.error('解析监视事件失败:', error)
+      }
+    })
+  }
+  return () => source.close()
+}
+
+/**
+ * 解析Excel文件
+ * @param {string} filePath - 文件路径
+ * @param {string} sheetName - 工作表名称，为空时读取第一个工作表
+ * @param {string} optionSeparator - 选项分隔符
+ * @param {string} answerSeparator - 答案分隔符
+ * @returns {Promise<Array>} 解析结果
+ */
+export async function parseExcelFile(filePath, sheetName, optionSeparator, answerSeparator) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/parse-excel`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        filePath,
+        sheetName,
+        optionSeparator,
+        answerSeparator
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || 'Excel解析失败')
+    }
+
+    return data.results || []
+  } catch (error) {
+    console.error('Excel解析失败:', error)
+    throw error
+  }
+}
+
 /**
  * 设置全局答案
  * @param {Array} answers - 答案数组
@@ -112,6 +641,66 @@ export async function setGlobalAnswers(answers) {
   }
 }
 
+/**
+ * 导入题库
+ * @param {string} name - 题库名称，为空时使用文件名
+ * @param {string} sourceFile - 来源文件路径
+ * @param {Array} answers - 答案数组
+ * @returns {Promise<Object>} 新建的题库信息
+ */
+export async function importBank(name, sourceFile, answers) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/import-bank`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        name,
+        sourceFile,
+        answers
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '导入题库失败')
+    }
+
+    return data.bank
+  } catch (error) {
+    console.error('导入题库失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 导出题库为JSON、JSONL或YAML格式的文本
+ * @param {string} id - 题库ID，为空时导出所有启用题库中的题目
+ * @param {string} format - 格式：json、jsonl 或 yaml
+ * @returns {Promise<string>} 题库文件内容
+ */
+export async function exportBank(id = '', format = 'json') {
+  try {
+    const params = new URLSearchParams({ id, format })
+    const response = await fetch(`${API_BASE_URL}/api/export-bank?${params}`)
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    return await response.text()
+  } catch (error) {
+    console.error('导出题库失败:', error)
+    throw error
+  }
+}
+
 /**
  * 获取全局答案
  * @returns {Promise<Array>} 全局答案数组
@@ -176,13 +765,82 @@ export async function testOCRConnection(config) {
   }
 }
 
+/**
+ * 获取所有显示器
+ * @returns {Promise<Array>} 显示器列表，坐标和尺寸为物理像素
+ */
+export async function listDisplays() {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/displays`)
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '获取显示器失败')
+    }
+
+    return data.displays || []
+  } catch (error) {
+    console.error('获取显示器失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取截图环境诊断信息，列出各截图工具是否可用
+ * @returns {Promise<Object>} 诊断信息
+ */
+export async function getScreenshotDiagnostics() {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/screenshot-diagnostics`)
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '获取截图诊断信息失败')
+    }
+
+    return data.diagnostics
+  } catch (error) {
+    console.error('获取截图诊断信息失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 设置截图文件，设置后截图直接读取该文件，便于测试
+ * @param {string} path - 图片文件路径，为空时恢复截取屏幕
+ * @returns {Promise<Object>} 诊断信息
+ */
+export async function setScreenshotFile(path) {
+  try {
+    // 截图文件只能在应用内设置，HTTP接口不提供修改
+    const { ExamService } = await import('../../bindings/changeme/index.js')
+    await ExamService.SetScreenshotFile(path)
+    return await getScreenshotDiagnostics()
+  } catch (error) {
+    console.error('设置截图文件失败:', error)
+    throw error
+  }
+}
+
 /**
  * 截图
+ * @param {string} displayId - 显示器标识，为空时截取主显示器，为 'virtual' 时截取整个虚拟桌面
  * @returns {Promise<string>} 截图结果（base64图片数据）
  */
-export async function takeScreenshot() {
+export async function takeScreenshot(displayId = '') {
   try {
-    const response = await fetch(`${API_BASE_URL}/api/take-screenshot`, {
+    const query = displayId ? `?display=${encodeURIComponent(displayId)}` : ''
+    const response = await fetch(`${API_BASE_URL}/api/take-screenshot${query}`, {
       method: 'POST',
       headers: {
         'Content-Type': 'application/json',
@@ -206,6 +864,62 @@ export async function takeScreenshot() {
   }
 }
 
+/**
+ * 截图并缓存在后端，只返回截图标识，OCR时用 captureId 代替图片数据
+ * @param {string} displayId - 显示器标识，为空时截取主显示器
+ * @returns {Promise<Object>} 截图信息，包含 captureId、width、height
+ */
+export async function captureScreenshot(displayId = '') {
+  try {
+    const query = new URLSearchParams({ image: 'false' })
+    if (displayId) {
+      query.set('display', displayId)
+    }
+    const response = await fetch(`${API_BASE_URL}/api/take-screenshot?${query}`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      }
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '截图失败')
+    }
+
+    return {
+      captureId: data.captureId,
+      width: data.width,
+      height: data.height
+    }
+  } catch (error) {
+    console.error('截图失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 获取缓存截图的图片地址，可直接用作 img 的 src
+ * @param {string} captureId - 截图标识
+ * @param {Object} area - 裁剪区域，为空时返回完整截图
+ * @returns {string} 图片地址
+ */
+export function captureImageURL(captureId, area = null) {
+  const query = new URLSearchParams({ id: captureId })
+  if (area && area.width && area.height) {
+    query.set('x', Math.round(area.x))
+    query.set('y', Math.round(area.y))
+    query.set('width', Math.round(area.width))
+    query.set('height', Math.round(area.height))
+  }
+  return `${API_BASE_URL}/api/capture-image?${query}`
+}
+
 /**
  * 执行OCR
  * @param {Object} area - 截图区域
@@ -242,6 +956,157 @@ export async function performOCR(area, config) {
   }
 }
 
+/**
+ * 执行OCR并返回识别文字的平均置信度
+ * @param {Object} area - 截图区域
+ * @param {Object} config - OCR配置
+ * @returns {Promise<Object>} 包含识别结果 result 和置信度 confidence（0表示未知）
+ */
+export async function performOCRWithConfidence(area, config) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/perform-ocr`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        area,
+        config
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || 'OCR执行失败')
+    }
+
+    return {
+      result: data.result || '',
+      confidence: data.confidence || 0
+    }
+  } catch (error) {
+    console.error('OCR执行失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 执行OCR并返回预处理后的图片，用于调试预处理方案
+ * @param {Object} area - 截图区域
+ * @param {Object} config - OCR配置
+ * @returns {Promise<Object>} 包含识别结果 result 和预处理后图片 image
+ */
+export async function performOCRDebug(area, config) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/perform-ocr`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        area,
+        config: { ...config, debug: true }
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || 'OCR执行失败')
+    }
+
+    return {
+      result: data.result || '',
+      image: data.image || ''
+    }
+  } catch (error) {
+    console.error('OCR执行失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 执行OCR识别并解析为结构化题目
+ * @param {Object} area - 截图区域
+ * @param {Object} config - OCR配置
+ * @returns {Promise<Object>} 结构化题目，包含题号、题型、题干和选项
+ */
+export async function performOCRParsed(area, config) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/perform-ocr`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        area,
+        config,
+        parse: true
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || 'OCR执行失败')
+    }
+
+    return data.parsed
+  } catch (error) {
+    console.error('OCR执行失败:', error)
+    throw error
+  }
+}
+
+/**
+ * 按结构化题目搜索答案
+ * @param {Object} parsed - 结构化题目
+ * @param {Object} filters - 筛选条件
+ * @returns {Promise<Array>} 搜索结果
+ */
+export async function searchParsedQuestion(parsed, filters = {}) {
+  try {
+    const response = await fetch(`${API_BASE_URL}/api/search`, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+      },
+      body: JSON.stringify({
+        filters,
+        parsed
+      })
+    })
+
+    if (!response.ok) {
+      throw new Error(`HTTP请求失败: ${response.status} ${response.statusText}`)
+    }
+
+    const data = await response.json()
+    
+    if (!data.success) {
+      throw new Error(data.message || '搜索失败')
+    }
+
+    return data.results || []
+  } catch (error) {
+    console.error('搜索答案失败:', error)
+    throw error
+  }
+}
+
 /**
  * 测试HTTP连接
  * @returns {Promise<boolean>} 连接是否成功
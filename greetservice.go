@@ -1,31 +1,19 @@
 package main
 
 import (
-	"bytes"
-	"encoding/base64"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/png"
-	"io"
 	"log"
-	"mime/multipart"
+	"math"
 	"net/http"
-	"net/url"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"runtime"
 	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
-	"golang.org/x/text/encoding"
-	"golang.org/x/text/encoding/simplifiedchinese"
-	"golang.org/x/text/transform"
-
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -34,10 +22,19 @@ type ExamService struct{}
 
 // OCRConfig OCR配置
 type OCRConfig struct {
-	Mode   string `json:"mode"`   // "online" 或 "local"
+	Mode   string `json:"mode"`   // "local"、"online" 或 "tesseract"，见 OCREngine
 	URL    string `json:"url"`    // 在线OCR URL
 	APIKey string `json:"apiKey"` // API密钥
 	Status string `json:"status"` // 连接状态
+
+	Tesseract TesseractConfig `json:"tesseract,omitempty"` // tesseract 模式的命令行配置
+
+	Preprocess string                       `json:"preprocess,omitempty"` // 识别前使用的预处理方案名称，为空时不处理
+	Profiles   map[string]PreprocessProfile `json:"profiles,omitempty"`   // 自定义预处理方案，同名时覆盖内置方案
+	Debug      bool                         `json:"debug,omitempty"`      // 在HTTP响应中返回预处理后的图片
+
+	MinConfidence      float64 `json:"minConfidence,omitempty"`      // 丢弃整体置信度低于此值的识别结果，默认0.3
+	WildcardConfidence float64 `json:"wildcardConfidence,omitempty"` // 置信度低于此值的字符在搜索时作为通配符，仅对提供逐字置信度的引擎生效，默认0.6
 }
 
 // ImportConfig 导入配置
@@ -49,25 +46,34 @@ type ImportConfig struct {
 
 // ScreenshotArea 截图区域
 type ScreenshotArea struct {
-	X      int    `json:"x"`
-	Y      int    `json:"y"`
-	Width  int    `json:"width"`
-	Height int    `json:"height"`
-	Image  string `json:"image"` // base64编码的图片
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Image     string `json:"image"`               // base64编码的图片或 data URL，支持PNG、JPEG和WebP
+	CaptureID string `json:"captureId,omitempty"` // 缓存中的截图标识，设置后不需要传递图片数据
+	DisplayID string `json:"displayId,omitempty"` // 所在显示器标识，为空时表示主显示器
+
+	screenshot image.Image // 进程内截取的截图，优先于 CaptureID 和 Image
 }
 
 // AnswerItem 答案项
 type AnswerItem struct {
-	Type     string   `json:"type"`     // 题目类型
-	Question string   `json:"question"` // 题目内容
-	Options  []string `json:"options"`  // 选项
-	Answer   []string `json:"answer"`   // 答案
+	Type        string            `json:"type" yaml:"type"`                                   // 题目类型
+	Question    string            `json:"question" yaml:"question"`                           // 题目内容
+	Options     []string          `json:"options" yaml:"options"`                             // 选项
+	Answer      []string          `json:"answer" yaml:"answer"`                               // 答案
+	Explanation string            `json:"explanation,omitempty" yaml:"explanation,omitempty"` // 解析
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`               // 标签，如章节、知识点
+	Difficulty  string            `json:"difficulty,omitempty" yaml:"difficulty,omitempty"`   // 难度
+	Source      string            `json:"source,omitempty" yaml:"source,omitempty"`           // 来源，如教材页码、真题年份
+	Extra       map[string]string `json:"extra,omitempty" yaml:"extra,omitempty"`             // 未映射到标准字段的列，key为表头
 }
 
 // 校验过程可能返回类型
 type HeaderError struct {
-	Missing []string // 缺失字段
-	Extra   []string // 多余字段
+	Missing []string `json:"missing"` // 缺失字段
+	Extra   []string `json:"extra"`   // 多余字段
 }
 
 func (e HeaderError) Error() string {
@@ -83,12 +89,16 @@ func (e HeaderError) Error() string {
 
 // SearchResult 搜索结果
 type SearchResult struct {
-	Item            AnswerItem       `json:"item"`
-	Score           float64          `json:"score"`           // 匹配度
-	Matched         string           `json:"matched"`         // 匹配的文本
-	QuestionMatches []int            `json:"questionMatches"` // 题目匹配位置
-	OptionMatches   map[string][]int `json:"optionMatches"`   // 选项匹配位置，key为选项文本
-	AnswerMatches   []int            `json:"answerMatches"`   // 答案匹配位置（不使用）
+	Item               AnswerItem       `json:"item"`
+	Score              float64          `json:"score"`              // 匹配度
+	Matched            string           `json:"matched"`            // 匹配的文本
+	QuestionMatches    []int            `json:"questionMatches"`    // 题目匹配位置
+	OptionMatches      map[string][]int `json:"optionMatches"`      // 选项匹配位置，key为选项文本
+	AnswerMatches      []int            `json:"answerMatches"`      // 答案匹配位置（不使用）
+	ExplanationMatches []int            `json:"explanationMatches"` // 解析匹配位置
+	BankID             string           `json:"bankId"`             // 所属题库ID
+	BankName           string           `json:"bankName"`           // 所属题库名称
+	OCRConfidence      float64          `json:"ocrConfidence"`      // 查询文本的OCR平均置信度，0表示未知
 }
 
 // FileDialogResult 文件对话框结果
@@ -100,9 +110,10 @@ type FileDialogResult struct {
 
 // OCRResult OCR识别结果
 type OCRResult struct {
-	Text       string  `json:"text"`
-	Confidence float64 `json:"confidence"`
-	BBox       struct {
+	Text            string    `json:"text"`
+	Confidence      float64   `json:"confidence"`
+	CharConfidences []float64 `json:"charConfidences,omitempty"` // 每个字符的置信度，引擎支持时提供
+	BBox            struct {
 		XMin   int     `json:"xmin"`
 		YMin   int     `json:"ymin"`
 		XMax   int     `json:"xmax"`
@@ -131,50 +142,8 @@ type OCRService struct {
 
 // ProcessImage 处理图片进行OCR识别
 func (o *OCRService) ProcessImage(imageData []byte) ([]OCRResult, error) {
-	// 将图片数据编码为base64
-	base64Data := base64.StdEncoding.EncodeToString(imageData)
-
-	// 准备请求数据
-	requestData := map[string]string{
-		"image": base64Data,
-	}
-
-	jsonData, err := json.Marshal(requestData)
-	if err != nil {
-		return nil, fmt.Errorf("编码请求数据失败: %v", err)
-	}
-
-	// 发送HTTP请求
-	req, err := http.NewRequest("POST", o.ServerURL+"/ocr", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("创建OCR请求失败: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := o.Client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("发送OCR请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取OCR响应失败: %v", err)
-	}
-
-	// 解析JSON响应
-	var ocrResp OCRResponse
-	err = json.Unmarshal(body, &ocrResp)
-	if err != nil {
-		return nil, fmt.Errorf("解析OCR响应失败: %v", err)
-	}
-
-	if !ocrResp.Success {
-		return nil, fmt.Errorf("OCR服务返回错误")
-	}
-
-	return ocrResp.Data.Results, nil
+	engine := &localOCREngine{Client: o.Client}
+	return engine.Recognize(imageData, OCRConfig{Mode: OCRModeLocal, URL: o.ServerURL})
 }
 
 // OpenFileDialog 打开文件对话框
@@ -190,6 +159,8 @@ func (e *ExamService) OpenFileDialog(title string, fileType string) (FileDialogR
 		dialog.AddFilter("CSV文件", "*.csv")
 	} else if fileType == "excel" {
 		dialog.AddFilter("Excel文件", "*.xlsx;*.xls")
+	} else if fileType == "bank" {
+		dialog.AddFilter("题库文件", "*.json;*.jsonl;*.yaml;*.yml")
 	}
 
 	// 允许选择所有文件类型
@@ -234,135 +205,62 @@ func (e *ExamService) OpenFileDialog(title string, fileType string) (FileDialogR
 	}, nil
 }
 
-// ReadFileContent 读取文件内容
-// getEncoding 根据编码名称获取对应的编码器
-func getEncoding(encodingName string) (encoding.Encoding, error) {
-	switch strings.ToLower(encodingName) {
-	case "utf8", "utf-8":
-		return nil, nil // UTF-8是默认编码
-	case "gbk", "gb2312":
-		return simplifiedchinese.GBK, nil
-	default:
-		return nil, fmt.Errorf("不支持的编码格式: %s，仅支持UTF-8和GBK", encodingName)
-	}
-}
-
+// ReadFileContent 读取文件内容，encoding 为 auto 时自动检测编码
 func (e *ExamService) ReadFileContent(filePath string, encoding string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", fmt.Errorf("无法打开文件: %v", err)
-	}
-	defer file.Close()
-
-	var reader io.Reader = file
-
-	// 根据编码处理文件
-	if encoding != "utf8" && encoding != "utf-8" {
-		enc, err := getEncoding(encoding)
-		if err != nil {
-			return "", fmt.Errorf("编码设置错误: %v", err)
-		}
-
-		if enc != nil {
-			reader = transform.NewReader(file, enc.NewDecoder())
-		}
-	}
-
-	// 读取文件内容
-	content, err := io.ReadAll(reader)
+	content, _, err := decodeFile(filePath, encoding)
 	if err != nil {
 		return "", fmt.Errorf("读取文件失败: %v", err)
 	}
 
-	return string(content), nil
+	return content, nil
 }
 
 // ParseCSVFile 解析CSV文件
+// encoding、optionSeparator、answerSeparator 可传 auto 自动检测，字段分隔符始终自动检测
 func (e *ExamService) ParseCSVFile(filePath string, encoding string, optionSeparator string, answerSeparator string) ([]AnswerItem, error) {
-	var answers []AnswerItem
+	answers, _, _, err := e.parseCSV(filePath, ImportOptions{
+		Encoding:        encoding,
+		OptionSeparator: optionSeparator,
+		AnswerSeparator: answerSeparator,
+	})
+	return answers, err
+}
 
-	// 打开文件
-	f, err := os.Open(filePath)
+// ParseCSVFileLenient 宽松模式解析CSV文件，跳过有问题的行并返回逐行诊断报告
+func (e *ExamService) ParseCSVFileLenient(filePath string, encoding string, optionSeparator string, answerSeparator string) (ImportResult, error) {
+	answers, report, settings, err := e.parseCSV(filePath, ImportOptions{
+		Encoding:        encoding,
+		OptionSeparator: optionSeparator,
+		AnswerSeparator: answerSeparator,
+		Lenient:         true,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("无法打开文件: %v", err)
-	}
-	defer f.Close()
-
-	// 解码器处理
-	var reader io.Reader
-	switch strings.ToLower(encoding) {
-	case "gbk", "gb2312":
-		reader = transform.NewReader(f, simplifiedchinese.GBK.NewDecoder())
-	case "utf-8", "utf8":
-		reader = f
-	default:
-		return nil, fmt.Errorf("不支持的编码格式: %s", encoding)
+		return ImportResult{}, err
 	}
+	return ImportResult{Answers: answers, Report: report, Settings: &settings}, nil
+}
 
-	csvReader := csv.NewReader(reader)
-	csvReader.TrimLeadingSpace = true
-
-	// 读取标题行
-	headers, err := csvReader.Read()
+// parseCSV 解析CSV文件，返回实际使用的编码和分隔符
+func (e *ExamService) parseCSV(filePath string, options ImportOptions) ([]AnswerItem, *ImportReport, DetectedSettings, error) {
+	// 读取并解码文件
+	text, settings, err := decodeFile(filePath, options.Encoding)
 	if err != nil {
-		return nil, fmt.Errorf("读取标题行失败: %v", err)
+		return nil, nil, settings, err
 	}
 
-	expected := map[string]int{"类型": -1, "题目": -1, "选项": -1, "答案": -1}
-	for i, h := range headers {
-		if _, ok := expected[h]; ok {
-			expected[h] = i
-		}
-	}
-
-	// 检查缺失字段
-	var missing []string
-	for key, idx := range expected {
-		if idx == -1 {
-			missing = append(missing, key)
-		}
-	}
-	if len(missing) > 0 {
-		return nil, fmt.Errorf("缺少字段: %s", strings.Join(missing, ", "))
-	}
-
-	// 读取数据行
-	for {
-		record, err := csvReader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("读取数据失败: %v", err)
-		}
-
-		answer := AnswerItem{
-			Type:     strings.TrimSpace(record[expected["类型"]]),
-			Question: strings.TrimSpace(record[expected["题目"]]),
-			Options:  []string{},
-			Answer:   []string{},
-		}
-
-		// 拆分选项
-		optionsStr := record[expected["选项"]]
-		if optionSeparator != "" {
-			separator := e.parseSeparator(optionSeparator)
-			answer.Options = strings.Split(optionsStr, separator)
-		} else {
-			answer.Options = []string{optionsStr}
-		}
-
-		// 拆分答案
-		answerStr := record[expected["答案"]]
-		if answerStr != "" {
-			separator := e.parseSeparator(answerSeparator)
-			answer.Answer = strings.Split(answerStr, separator)
-		}
+	delimiter := detectDelimiter(text)
+	settings.Delimiter = string(delimiter)
 
-		answers = append(answers, answer)
+	csvReader := csv.NewReader(strings.NewReader(text))
+	csvReader.Comma = delimiter
+	csvReader.TrimLeadingSpace = true
+	if options.Lenient {
+		// 列数检查交给诊断报告处理
+		csvReader.FieldsPerRecord = -1
 	}
 
-	return answers, nil
+	answers, report, err := e.parseTable(&csvRows{reader: csvReader}, options, true, &settings)
+	return answers, report, settings, err
 }
 
 // parseSeparator 解析分隔符，支持转义字符
@@ -383,56 +281,13 @@ func (e *ExamService) parseSeparator(separator string) string {
 
 // TestOCRConnection 测试OCR连接
 func (e *ExamService) TestOCRConnection(config OCRConfig) (string, error) {
-	if config.URL == "" {
-		return "连接失败", fmt.Errorf("未配置OCR服务URL")
-	}
-
-	// 构建健康检查URL
-	healthURL := config.URL
-	if !strings.HasSuffix(healthURL, "/") {
-		healthURL += "/"
-	}
-	healthURL += "health"
-
-	// 发送HTTP请求到健康检查端点
-	req, err := http.NewRequest("GET", healthURL, nil)
-	if err != nil {
-		return "连接失败", fmt.Errorf("创建健康检查请求失败: %v", err)
-	}
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	engine, err := ocrEngineFor(config)
 	if err != nil {
-		return "连接失败", fmt.Errorf("健康检查请求失败: %v", err)
+		return "连接失败", err
 	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "连接失败", fmt.Errorf("读取健康检查响应失败: %v", err)
-	}
-
-	// 检查HTTP状态码
-	if resp.StatusCode != 200 {
-		return "连接失败", fmt.Errorf("健康检查失败，状态码: %d", resp.StatusCode)
+	if err := engine.Check(config); err != nil {
+		return "连接失败", err
 	}
-
-	// 尝试解析JSON响应
-	var healthResp struct {
-		Success bool   `json:"success"`
-		Message string `json:"message"`
-	}
-
-	if err := json.Unmarshal(body, &healthResp); err == nil {
-		if healthResp.Success {
-			return "连接成功", nil
-		} else {
-			return "连接失败", fmt.Errorf("OCR服务报告错误: %s", healthResp.Message)
-		}
-	}
-
-	// 如果无法解析JSON，但HTTP状态码是200，也认为连接成功
 	return "连接成功", nil
 }
 
@@ -449,10 +304,11 @@ func (e *ExamService) TestLocalOCR() (string, error) {
 	}
 
 	// 使用新的OCR服务处理图像
-	result, err := e.performOCRWithURL(imageData, defaultURL)
+	results, err := e.recognize(imageData, OCRConfig{Mode: OCRModeLocal, URL: defaultURL})
 	if err != nil {
 		return "", fmt.Errorf("OCR处理失败: %v", err)
 	}
+	result := joinOCRText(results)
 
 	if result == "" {
 		return "未检测到任何文本内容", nil
@@ -461,59 +317,32 @@ func (e *ExamService) TestLocalOCR() (string, error) {
 	return fmt.Sprintf("OCR处理完成，识别结果：\n%s", result), nil
 }
 
-// TakeScreenshot 截取屏幕
+// TakeScreenshot 截取主显示器，返回PNG格式的 data URL
 func (e *ExamService) TakeScreenshot() (string, error) {
-	var cmd *exec.Cmd
-	var tempFile string
-
-	// 根据操作系统选择不同的截图命令
-	switch runtime.GOOS {
-	case "darwin": // macOS
-		tempFile = "/tmp/screenshot.png"
-		cmd = exec.Command("screencapture", "-x", "-t", "png", tempFile)
-	case "windows": // Windows
-		tempFile = filepath.Join(os.TempDir(), "screenshot.png")
-		cmd = exec.Command("powershell", "-Command", "Add-Type -AssemblyName System.Windows.Forms; Add-Type -AssemblyName System.Drawing; $screen = [System.Windows.Forms.Screen]::PrimaryScreen; $bitmap = New-Object System.Drawing.Bitmap $screen.Bounds.Width, $screen.Bounds.Height; $graphics = [System.Drawing.Graphics]::FromImage($bitmap); $graphics.CopyFromScreen($screen.Bounds.X, $screen.Bounds.Y, 0, 0, $screen.Bounds.Size); $bitmap.Save('"+tempFile+"', [System.Drawing.Imaging.ImageFormat]::Png); $graphics.Dispose(); $bitmap.Dispose()")
-	case "linux": // Linux
-		tempFile = "/tmp/screenshot.png"
-		cmd = exec.Command("import", "-window", "root", tempFile)
-	default:
-		return "", fmt.Errorf("不支持的操作系统: %s", runtime.GOOS)
-	}
-
-	err := cmd.Run()
-	if err != nil {
-		return "", fmt.Errorf("截图失败: %v", err)
-	}
+	return e.TakeDisplayScreenshot("")
+}
 
-	// 读取截图文件
-	imageData, err := os.ReadFile(tempFile)
+// TakeScreenshotWithWindowControl 带窗口控制的截图
+func (e *ExamService) TakeScreenshotWithWindowControl() (string, error) {
+	img, err := e.takeScreenshotWithWindowControl("", "")
 	if err != nil {
-		return "", fmt.Errorf("读取截图文件失败: %v", err)
+		return "", err
 	}
-
-	// 清理临时文件
-	os.Remove(tempFile)
-
-	// 转换为base64编码
-	base64Data := base64.StdEncoding.EncodeToString(imageData)
-
-	// 返回data URL格式
-	return "data:image/png;base64," + base64Data, nil
+	return imageDataURL(img)
 }
 
-// TakeScreenshotWithWindowControl 带窗口控制的截图
-func (e *ExamService) TakeScreenshotWithWindowControl() (string, error) {
+// takeScreenshotWithWindowControl 隐藏应用窗口后截取指定显示器
+func (e *ExamService) takeScreenshotWithWindowControl(displayID, displayName string) (image.Image, error) {
 	// 获取应用实例
 	app := application.Get()
 	if app == nil {
-		return "", fmt.Errorf("无法获取应用实例")
+		return nil, fmt.Errorf("无法获取应用实例")
 	}
 
 	// 获取所有窗口
 	windows := app.Window.GetAll()
 	if len(windows) == 0 {
-		return "", fmt.Errorf("没有找到窗口")
+		return nil, fmt.Errorf("没有找到窗口")
 	}
 
 	window := windows[0]
@@ -525,11 +354,11 @@ func (e *ExamService) TakeScreenshotWithWindowControl() (string, error) {
 	time.Sleep(500 * time.Millisecond)
 
 	// 3. 截取屏幕
-	screenshot, err := e.TakeScreenshot()
+	screenshot, err := e.captureDisplay(displayID, displayName)
 	if err != nil {
 		// 即使截图失败也要恢复窗口
 		window.Restore()
-		return "", err
+		return nil, err
 	}
 
 	// 4. 恢复窗口
@@ -554,213 +383,67 @@ func (e *ExamService) SelectArea(screenshotData string) (ScreenshotArea, error)
 
 // PerformOCR 执行OCR识别
 func (e *ExamService) PerformOCR(area ScreenshotArea, config OCRConfig) (string, error) {
-	if area.Image == "" {
-		return "", fmt.Errorf("没有截图数据")
-	}
-
-	// 解码base64图片数据
-	imageData, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(area.Image, "data:image/png;base64,"))
-	if err != nil {
-		return "", fmt.Errorf("图片解码失败: %v", err)
-	}
-
-	// 解码PNG图片
-	img, err := png.Decode(bytes.NewReader(imageData))
-	if err != nil {
-		return "", fmt.Errorf("图片解码失败: %v", err)
-	}
-
-	// 如果指定了区域，裁剪图片
-	if area.Width > 0 && area.Height > 0 {
-		bounds := img.Bounds()
-		if area.X+area.Width <= bounds.Dx() && area.Y+area.Height <= bounds.Dy() {
-			img = img.(interface {
-				SubImage(r image.Rectangle) image.Image
-			}).SubImage(image.Rect(area.X, area.Y, area.X+area.Width, area.Y+area.Height))
-		}
-	}
-
-	// 重新编码为PNG
-	var buf bytes.Buffer
-	err = png.Encode(&buf, img)
+	run, err := e.runOCR(area, config)
 	if err != nil {
-		return "", fmt.Errorf("图片编码失败: %v", err)
-	}
-
-	// 使用配置的OCR服务URL进行识别
-	if config.URL != "" {
-		return e.performOCRWithURL(buf.Bytes(), config.URL)
+		return "", err
 	}
-
-	// 如果没有配置OCR URL，返回模拟结果
-	return "这是一个模拟的OCR识别结果", nil
+	return joinOCRText(run.results), nil
 }
 
-// performOCRWithURL 使用指定URL的OCR服务进行识别
-func (e *ExamService) performOCRWithURL(imageData []byte, serverURL string) (string, error) {
-	// 将图片数据编码为base64
-	base64Data := base64.StdEncoding.EncodeToString(imageData)
-
-	// 准备请求数据
-	requestData := map[string]string{
-		"image": base64Data,
-	}
-
-	jsonData, err := json.Marshal(requestData)
-	if err != nil {
-		return "", fmt.Errorf("编码请求数据失败: %v", err)
-	}
-
-	// 构建OCR请求URL
-	ocrURL := serverURL
-	if !strings.HasSuffix(ocrURL, "/") {
-		ocrURL += "/"
-	}
-	ocrURL += "ocr"
+// ocrRun 一次OCR识别的结果
+type ocrRun struct {
+	results    []OCRResult // 已按置信度处理的识别结果
+	image      []byte      // 交给OCR引擎的PNG图片
+	confidence float64     // 按字符加权的平均置信度，引擎未提供置信度时为0
 
-	// 发送HTTP请求
-	req, err := http.NewRequest("POST", ocrURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("创建OCR请求失败: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("发送OCR请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("读取OCR响应失败: %v", err)
-	}
-
-	// 解析JSON响应
-	var ocrResp OCRResponse
-	err = json.Unmarshal(body, &ocrResp)
-	if err != nil {
-		return "", fmt.Errorf("解析OCR响应失败: %v", err)
-	}
-
-	if !ocrResp.Success {
-		return "", fmt.Errorf("OCR服务返回错误")
-	}
-
-	// 只保留文字内容，合并所有识别结果
-	var allText strings.Builder
-	for i, result := range ocrResp.Data.Results {
-		if i > 0 {
-			allText.WriteString(" ")
-		}
-		allText.WriteString(strings.TrimSpace(result.Text))
-	}
-
-	return allText.String(), nil
+	// 各阶段耗时
+	cropTime       time.Duration
+	preprocessTime time.Duration // 包含编码为PNG的耗时
+	recognizeTime  time.Duration
 }
 
-// performOnlineOCR 使用在线OCR服务
-func (e *ExamService) performOnlineOCR(imageData []byte, config OCRConfig) (string, error) {
-	// 准备表单数据
-	formData := url.Values{}
-	formData.Set("apikey", config.APIKey)
-	formData.Set("language", "chs")
-	formData.Set("isOverlayRequired", "false")
-	formData.Set("filetype", "png")
-	formData.Set("detectOrientation", "true")
+// runOCR 裁剪并预处理截图后使用配置的OCR引擎识别，低置信度的文字按配置丢弃或替换为通配符
+// 截图只在交给OCR引擎前编码一次
+func (e *ExamService) runOCR(area ScreenshotArea, config OCRConfig) (ocrRun, error) {
+	var run ocrRun
 
-	// 创建multipart表单
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// 添加文件
-	part, err := writer.CreateFormFile("file", "screenshot.png")
-	if err != nil {
-		return "", fmt.Errorf("创建表单失败: %v", err)
-	}
-	_, err = part.Write(imageData)
+	start := time.Now()
+	img, err := area.source()
 	if err != nil {
-		return "", fmt.Errorf("写入图片数据失败: %v", err)
+		return ocrRun{}, err
 	}
+	img = cropImage(img, area)
+	run.cropTime = time.Since(start)
 
-	// 添加其他参数
-	for key, values := range formData {
-		for _, value := range values {
-			err := writer.WriteField(key, value)
-			if err != nil {
-				return "", fmt.Errorf("写入表单字段失败: %v", err)
-			}
-		}
-	}
-
-	err = writer.Close()
-	if err != nil {
-		return "", fmt.Errorf("关闭表单失败: %v", err)
-	}
-
-	// 发送HTTP请求
-	req, err := http.NewRequest("POST", config.URL, &buf)
+	start = time.Now()
+	img, err = preprocessImage(img, config)
 	if err != nil {
-		return "", fmt.Errorf("创建请求失败: %v", err)
+		return ocrRun{}, err
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	imageData, err := encodePNG(img)
 	if err != nil {
-		return "", fmt.Errorf("发送请求失败: %v", err)
+		return ocrRun{}, err
 	}
-	defer resp.Body.Close()
+	run.preprocessTime = time.Since(start)
 
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	start = time.Now()
+	results, err := e.recognize(imageData, config)
 	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %v", err)
-	}
-
-	// 解析JSON响应
-	var result struct {
-		ParsedResults []struct {
-			ParsedText string `json:"ParsedText"`
-		} `json:"ParsedResults"`
-		ErrorMessage string `json:"ErrorMessage"`
+		return ocrRun{}, err
 	}
+	run.recognizeTime = time.Since(start)
 
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
-	}
-
-	if result.ErrorMessage != "" {
-		return "", fmt.Errorf("OCR服务错误: %s", result.ErrorMessage)
-	}
-
-	if len(result.ParsedResults) == 0 {
-		return "", fmt.Errorf("没有识别到文本")
-	}
-
-	return strings.TrimSpace(result.ParsedResults[0].ParsedText), nil
+	run.results, run.confidence = applyOCRConfidence(results, config)
+	run.image = imageData
+	return run, nil
 }
 
 // normalizeText 标准化文本，移除或替换特殊字符以提高匹配率
 func (e *ExamService) normalizeText(text string) string {
-	// 移除常见的标点符号和特殊字符，但保留中文字符
-	// 这些字符在OCR识别中经常出现，但在语义匹配时应该被忽略
-	replacer := strings.NewReplacer(
-		"(", "", ")", "", "[", "", "]", "", "{", "", "}", "",
-		"（", "", "）", "", "【", "", "】", "", "《", "", "》", "",
-		"\"", "", "'", "", "`", "", "~", "", "!", "", "@", "",
-		"#", "", "$", "", "%", "", "^", "", "&", "", "*", "",
-		"+", "", "=", "", "|", "", "\\", "", "/", "", "?", "",
-		"<", "", ">", "", ",", "", ".", "", ";", "", ":", "",
-		"、", "", "，", "", "。", "", "；", "", "：", "", "！", "",
-		"？", "", "…", "", "—", "", "－", "", "·", "", "·", "",
-		"　", " ", "  ", " ", // 多个空格替换为单个空格
-	)
-
-	normalized := replacer.Replace(text)
+	// 全角转半角、繁体转简体，消除OCR和题库录入的字形差异
+	normalized := strings.Map(foldRune, text)
+
+	normalized = normalizeReplacer.Replace(normalized)
 
 	// 移除多余的空格
 	normalized = strings.TrimSpace(normalized)
@@ -773,6 +456,20 @@ func (e *ExamService) normalizeText(text string) string {
 	return normalized
 }
 
+// normalizeReplacer 移除常见的标点符号和特殊字符，但保留中文字符
+// 这些字符在OCR识别中经常出现，但在语义匹配时应该被忽略
+var normalizeReplacer = strings.NewReplacer(
+	"(", "", ")", "", "[", "", "]", "", "{", "", "}", "",
+	"（", "", "）", "", "【", "", "】", "", "《", "", "》", "",
+	"\"", "", "'", "", "`", "", "~", "", "!", "", "@", "",
+	"#", "", "$", "", "%", "", "^", "", "&", "", "*", "",
+	"+", "", "=", "", "|", "", "\\", "", "/", "", "?", "",
+	"<", "", ">", "", ",", "", ".", "", ";", "", ":", "",
+	"、", "", "，", "", "。", "", "；", "", "：", "", "！", "",
+	"？", "", "…", "", "—", "", "－", "", "·", "", "·", "",
+	"　", " ", "  ", " ", // 多个空格替换为单个空格
+)
+
 // SearchAnswers 搜索答案
 // AccuracyFilters 准确度筛选参数
 type AccuracyFilters struct {
@@ -781,9 +478,85 @@ type AccuracyFilters struct {
 	Low    bool `json:"low"`    // 低准确率 (<50%)
 }
 
-func (e *ExamService) SearchAnswers(answers []AnswerItem, query string, filters AccuracyFilters) ([]SearchResult, error) {
+// accept 判断分数是否满足准确度筛选
+func (f AccuracyFilters) accept(score float64) bool {
+	// 如果所有过滤器都为false，显示所有结果
+	if !f.High && !f.Medium && !f.Low {
+		return true
+	}
+
+	// 否则按过滤器筛选
+	if score >= 0.8 {
+		return f.High
+	} else if score >= 0.5 {
+		return f.Medium
+	}
+	return f.Low
+}
+
+// SearchFilters 搜索筛选参数
+type SearchFilters struct {
+	AccuracyFilters AccuracyFilters `json:"accuracyFilters"`
+	Tags            []string        `json:"tags,omitempty"`         // 标签，题目包含任一标签即可
+	Difficulties    []string        `json:"difficulties,omitempty"` // 难度，题目难度为其中之一即可
+	Source          string          `json:"source,omitempty"`       // 来源关键字
+	Ranking         string          `json:"ranking,omitempty"`      // 排序方式：overlap（默认，重合度）或 bm25
+}
+
+// matchItem 判断题目是否满足标签、难度和来源筛选
+func (f SearchFilters) matchItem(item AnswerItem) bool {
+	if len(f.Tags) > 0 {
+		found := false
+		for _, tag := range item.Tags {
+			for _, want := range f.Tags {
+				if strings.EqualFold(strings.TrimSpace(tag), strings.TrimSpace(want)) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.Difficulties) > 0 {
+		found := false
+		for _, want := range f.Difficulties {
+			if strings.EqualFold(strings.TrimSpace(item.Difficulty), strings.TrimSpace(want)) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	source := strings.ToLower(strings.TrimSpace(f.Source))
+	if source != "" && !strings.Contains(strings.ToLower(item.Source), source) {
+		return false
+	}
+
+	return true
+}
+
+// SearchAnswers 在给定题目中搜索答案，标签、难度和来源条件先于准确度筛选生效
+func (e *ExamService) SearchAnswers(answers []AnswerItem, query string, filters SearchFilters) ([]SearchResult, error) {
 	results := []SearchResult{}
 
+	// 先按标签、难度和来源筛选题目
+	filtered := answers[:0:0]
+	for _, answer := range answers {
+		if filters.matchItem(answer) {
+			filtered = append(filtered, answer)
+		}
+	}
+	answers = filtered
+
+	// BM25 排序，语料统计基于传入的题目
+	if filters.Ranking == RankingBM25 && strings.TrimSpace(query) != "" {
+		return e.searchBM25(newSearchIndex(answers), answers, query, filters), nil
+	}
+
 	// 预处理查询文本，移除特殊字符
 	normalizedQuery := e.normalizeText(query)
 	normalizedQuery = strings.ToLower(strings.TrimSpace(normalizedQuery))
@@ -793,12 +566,13 @@ func (e *ExamService) SearchAnswers(answers []AnswerItem, query string, filters
 		log.Println("查询为空，返回所有答案")
 		for _, answer := range answers {
 			results = append(results, SearchResult{
-				Item:            answer,
-				Score:           0.5, // 给予中等匹配度
-				Matched:         "全部结果",
-				QuestionMatches: []int{},
-				OptionMatches:   make(map[string][]int),
-				AnswerMatches:   []int{},
+				Item:               answer,
+				Score:              0.5, // 给予中等匹配度
+				Matched:            "全部结果",
+				QuestionMatches:    []int{},
+				OptionMatches:      make(map[string][]int),
+				AnswerMatches:      []int{},
+				ExplanationMatches: []int{},
 			})
 		}
 		return results, nil
@@ -864,6 +638,31 @@ func (e *ExamService) SearchAnswers(answers []AnswerItem, query string, filters
 			optionMatches[option] = optionMatchesForThis
 		}
 
+		// 计算解析、标签和来源重合度，权重低于题目和选项
+		explanationMatches := []int{}
+		if answer.Explanation != "" {
+			explanationLower := strings.ToLower(e.normalizeText(answer.Explanation))
+			explanationScore, _ := e.calculateOverlapScore(normalizedQuery, explanationLower)
+			explanationMatches = e.calculateMatchesForOriginalText(answer.Explanation, normalizedQuery)
+			explanationScore = explanationScore * 0.6
+			if explanationScore > maxScore {
+				maxScore = explanationScore
+				matched = "解析匹配: " + normalizedQuery
+			}
+		}
+		for _, meta := range append(append([]string{}, answer.Tags...), answer.Source) {
+			if meta == "" {
+				continue
+			}
+			metaLower := strings.ToLower(e.normalizeText(meta))
+			metaScore, _ := e.calculateOverlapScore(normalizedQuery, metaLower)
+			metaScore = metaScore * 0.5
+			if metaScore > maxScore {
+				maxScore = metaScore
+				matched = "标签匹配: " + normalizedQuery
+			}
+		}
+
 		score = maxScore
 
 		// 记录所有可能的匹配结果，包括低匹配度的
@@ -874,33 +673,18 @@ func (e *ExamService) SearchAnswers(answers []AnswerItem, query string, filters
 			}
 
 			// 根据准确度筛选
-			shouldInclude := false
-
-			// 如果所有过滤器都为false，显示所有结果
-			if !filters.High && !filters.Medium && !filters.Low {
-				shouldInclude = true
-			} else {
-				// 否则按过滤器筛选
-				if score >= 0.8 {
-					shouldInclude = filters.High
-				} else if score >= 0.5 {
-					shouldInclude = filters.Medium
-				} else {
-					shouldInclude = filters.Low
-				}
-			}
-
-			if shouldInclude {
+			if filters.AccuracyFilters.accept(score) {
 				log.Printf("搜索结果: 题目='%s', 分数=%.2f, 题目匹配=%v, 选项匹配=%v, 答案匹配=%v",
 					answer.Question, score, questionMatches, optionMatches, answerMatches)
 				log.Printf("filters: %v", filters)
 				allPossibleMatches = append(allPossibleMatches, SearchResult{
-					Item:            answer,
-					Score:           score,
-					Matched:         matched,
-					QuestionMatches: questionMatches,
-					OptionMatches:   optionMatches,
-					AnswerMatches:   answerMatches,
+					Item:               answer,
+					Score:              score,
+					Matched:            matched,
+					QuestionMatches:    questionMatches,
+					OptionMatches:      optionMatches,
+					AnswerMatches:      answerMatches,
+					ExplanationMatches: explanationMatches,
 				})
 			}
 		}
@@ -931,11 +715,15 @@ func (e *ExamService) calculateOverlapScore(query, text string) (float64, []int)
 	}
 
 	// 连续包含匹配 - 给予高匹配度
-	if strings.Contains(text, query) {
-		start := strings.Index(text, query)
-
+	// 查询中有低置信度通配符时，通配符可匹配任意字符
+	charStart := -1
+	if start := strings.Index(text, query); start >= 0 {
 		// 将字节位置转换为字符位置
-		charStart := utf8.RuneCountInString(text[:start])
+		charStart = utf8.RuneCountInString(text[:start])
+	} else if strings.ContainsRune(query, wildcardRune) {
+		charStart = wildcardIndex([]rune(text), []rune(query))
+	}
+	if charStart >= 0 {
 		charLen := utf8.RuneCountInString(query)
 
 		// 生成匹配位置的字符索引
@@ -982,7 +770,7 @@ func (e *ExamService) calculateSmartSimilarity(query, text string) (float64, []i
 		// 没有共同关键词，尝试使用编辑距离作为备选方案
 		editDistance := e.calculateEditDistance([]rune(query), []rune(text))
 		maxPossibleDistance := max(len(query), len(text))
-		editSimilarity := 1.0 - float64(editDistance)/float64(maxPossibleDistance)
+		editSimilarity := 1.0 - editDistance/float64(maxPossibleDistance)
 
 		// 降低阈值，允许更多可能的匹配
 		if editSimilarity > 0.3 {
@@ -995,7 +783,7 @@ func (e *ExamService) calculateSmartSimilarity(query, text string) (float64, []i
 	// 2. 计算编辑距离相似度
 	editDistance := e.calculateEditDistance([]rune(query), []rune(text))
 	maxPossibleDistance := max(len(query), len(text))
-	editSimilarity := 1.0 - float64(editDistance)/float64(maxPossibleDistance)
+	editSimilarity := 1.0 - editDistance/float64(maxPossibleDistance)
 
 	// 3. 计算关键词匹配度
 	keywordSimilarity := e.calculateKeywordSimilarity(query, text)
@@ -1050,6 +838,9 @@ func (e *ExamService) calculateCharSimilarity(query, text string) float64 {
 		}
 	}
 
+	// 低置信度通配符可以匹配目标文本中剩余的任意字符
+	commonChars += min(queryCharSet[wildcardRune], len(textChars)-commonChars)
+
 	// 计算相似度：共同字符数 / 总字符数
 	totalChars := len(queryChars) + len(textChars)
 	if totalChars == 0 {
@@ -1063,9 +854,11 @@ func (e *ExamService) calculateCharSimilarity(query, text string) float64 {
 // findCommonWords 查找共同的关键词
 func (e *ExamService) findCommonWords(s1, s2 string) []string {
 	// 将文本分割为单词
-	words1 := e.extractWords(s1)
-	words2 := e.extractWords(s2)
+	return commonWordsOf(e.extractWords(s1), e.extractWords(s2))
+}
 
+// commonWordsOf 查找两组单词中共同的单词
+func commonWordsOf(words1, words2 []string) []string {
 	// 找到共同的单词
 	common := make([]string, 0)
 	wordSet := make(map[string]bool)
@@ -1075,8 +868,9 @@ func (e *ExamService) findCommonWords(s1, s2 string) []string {
 	}
 
 	for _, word := range words2 {
-		if wordSet[word] && len(word) > 1 { // 忽略单字符单词
+		if wordSet[word] && utf8.RuneCountInString(word) > 1 { // 忽略单字符单词
 			common = append(common, word)
+			delete(wordSet, word) // 重复出现的单词只计一次
 		}
 	}
 
@@ -1088,14 +882,14 @@ func (e *ExamService) extractWords(text string) []string {
 	// 移除标点符号和特殊字符
 	normalized := e.normalizeText(text)
 
-	// 按空格分割
-	words := strings.Fields(normalized)
+	// 使用分词器切分，中文按词典分词
+	words := currentTokenizer().Tokenize(strings.ToLower(normalized))
 
-	// 过滤掉太短的单词
+	// 过滤掉单字，单字在中文里多为虚词，对关键词匹配意义不大
 	result := make([]string, 0)
 	for _, word := range words {
-		if len(word) > 1 {
-			result = append(result, strings.ToLower(word))
+		if utf8.RuneCountInString(word) > 1 {
+			result = append(result, word)
 		}
 	}
 
@@ -1112,7 +906,7 @@ func (e *ExamService) calculateKeywordSimilarity(s1, s2 string) float64 {
 	}
 
 	// 计算共同单词的数量
-	commonWords := e.findCommonWords(s1, s2)
+	commonWords := commonWordsOf(words1, words2)
 	commonCount := len(commonWords)
 
 	// 计算相似度：共同单词数 / 总单词数
@@ -1144,36 +938,37 @@ func (e *ExamService) calculateKeywordSimilarity(s1, s2 string) float64 {
 }
 
 // calculateEditDistance 计算编辑距离
-func (e *ExamService) calculateEditDistance(query, text []rune) int {
+// 替换OCR易混淆的字符（如 己/已、0/o）只计部分代价，见 confusables
+func (e *ExamService) calculateEditDistance(query, text []rune) float64 {
 	lenQuery := len(query)
 	lenText := len(text)
 
-	// 创建DP表
-	dp := make([][]int, lenQuery+1)
-	for i := range dp {
-		dp[i] = make([]int, lenText+1)
-	}
+	costs := confusables.current()
 
-	// 初始化第一行和第一列
-	for i := 0; i <= lenQuery; i++ {
-		dp[i][0] = i
-	}
+	// 只保留上一行和当前行
+	prev := make([]float64, lenText+1)
+	curr := make([]float64, lenText+1)
+
+	// 初始化第一行
 	for j := 0; j <= lenText; j++ {
-		dp[0][j] = j
+		prev[j] = float64(j)
 	}
 
 	// 填充DP表
 	for i := 1; i <= lenQuery; i++ {
+		curr[0] = float64(i)
 		for j := 1; j <= lenText; j++ {
 			if query[i-1] == text[j-1] {
-				dp[i][j] = dp[i-1][j-1]
+				curr[j] = prev[j-1]
 			} else {
-				dp[i][j] = min(dp[i-1][j], min(dp[i][j-1], dp[i-1][j-1])) + 1
+				substitute := prev[j-1] + costs.cost(query[i-1], text[j-1])
+				curr[j] = math.Min(math.Min(prev[j], curr[j-1])+1, substitute)
 			}
 		}
+		prev, curr = curr, prev
 	}
 
-	return dp[lenQuery][lenText]
+	return prev[lenText]
 }
 
 // calculateSimpleMatches 计算简化的匹配位置
@@ -1351,7 +1146,21 @@ func (e *ExamService) calculateMatchesForOriginalText(originalText, query string
 		}
 	}
 
-	return matches
+	// 同时高亮分词后的共同关键词在目标文本中的所有出现位置
+	for _, word := range e.findCommonWords(queryLower, originalLower) {
+		offset := 0
+		for {
+			start := strings.Index(originalLower[offset:], word)
+			if start == -1 {
+				break
+			}
+			charStart := utf8.RuneCountInString(originalLower[:offset+start])
+			charLen := utf8.RuneCountInString(word)
+			matches = append(matches, e.mapNormalizedPositionsToOriginal(originalText, normalizedOriginal, charStart, charLen)...)
+			offset += start + len(word)
+		}
+	}
+	return uniqueSortedInts(matches)
 }
 
 // mapNormalizedPositionsToOriginal 将标准化文本的位置映射回原始文本的位置
@@ -1394,34 +1203,35 @@ func (e *ExamService) mapNormalizedPositionsToOriginal(originalText, normalizedT
 
 // normalizeChar 标准化单个字符，返回标准化后的字符，如果字符被移除则返回0
 func (e *ExamService) normalizeChar(char rune) rune {
-	// 移除标点符号和特殊字符，与normalizeText函数保持一致
-	specialChars := map[rune]bool{
-		'(': true, ')': true, '[': true, ']': true, '{': true, '}': true,
-		'（': true, '）': true, '【': true, '】': true, '《': true, '》': true,
-		'"': true, '\'': true, '`': true, '~': true, '!': true, '@': true,
-		'#': true, '$': true, '%': true, '^': true, '&': true, '*': true,
-		'+': true, '=': true, '|': true, '\\': true, '/': true, '?': true,
-		'<': true, '>': true, ',': true, '.': true, ';': true, ':': true,
-		'、': true, '，': true, '。': true, '；': true, '：': true, '！': true,
-		'？': true, '…': true, '—': true, '－': true, '·': true, '　': true,
-	}
-
+	char = foldRune(char)
 	if specialChars[char] {
 		return 0 // 返回0表示字符被移除
 	}
 	return char
 }
 
+// specialChars 标准化时移除的标点符号和特殊字符，与normalizeText函数保持一致
+var specialChars = map[rune]bool{
+	'(': true, ')': true, '[': true, ']': true, '{': true, '}': true,
+	'（': true, '）': true, '【': true, '】': true, '《': true, '》': true,
+	'"': true, '\'': true, '`': true, '~': true, '!': true, '@': true,
+	'#': true, '$': true, '%': true, '^': true, '&': true, '*': true,
+	'+': true, '=': true, '|': true, '\\': true, '/': true, '?': true,
+	'<': true, '>': true, ',': true, '.': true, ';': true, ':': true,
+	'、': true, '，': true, '。': true, '；': true, '：': true, '！': true,
+	'？': true, '…': true, '—': true, '－': true, '·': true, '　': true,
+}
+
 // NextQuestion 下一题功能
 func (e *ExamService) NextQuestion(area ScreenshotArea, config OCRConfig) (string, error) {
-	// 1. 重新截图
-	screenshot, err := e.TakeScreenshot()
+	// 1. 重新截取区域所在的显示器
+	screenshot, err := e.captureDisplay(area.DisplayID, "")
 	if err != nil {
 		return "", err
 	}
 
 	// 2. 执行OCR识别
-	area.Image = screenshot
+	area.screenshot = screenshot
 	ocrResult, err := e.PerformOCR(area, config)
 	if err != nil {
 		return "", err
@@ -1436,11 +1246,9 @@ func (e *ExamService) findLongestCommonSubstring(s1, s2 string) string {
 		return ""
 	}
 
-	// 使用动态规划算法查找最长公共子串
-	dp := make([][]int, len(s1)+1)
-	for i := range dp {
-		dp[i] = make([]int, len(s2)+1)
-	}
+	// 使用动态规划算法查找最长公共子串，只保留上一行和当前行
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
 
 	maxLen := 0
 	endPos := 0
@@ -1448,13 +1256,16 @@ func (e *ExamService) findLongestCommonSubstring(s1, s2 string) string {
 	for i := 1; i <= len(s1); i++ {
 		for j := 1; j <= len(s2); j++ {
 			if s1[i-1] == s2[j-1] {
-				dp[i][j] = dp[i-1][j-1] + 1
-				if dp[i][j] > maxLen {
-					maxLen = dp[i][j]
+				curr[j] = prev[j-1] + 1
+				if curr[j] > maxLen {
+					maxLen = curr[j]
 					endPos = i - 1
 				}
+			} else {
+				curr[j] = 0
 			}
 		}
+		prev, curr = curr, prev
 	}
 
 	if maxLen == 0 {
@@ -1505,27 +1316,25 @@ func (e *ExamService) ShowWindow() error {
 	return nil
 }
 
-// 全局变量存储答案数据
-var globalAnswers []AnswerItem
-
-// SetGlobalAnswers 设置全局答案数据
-func (e *ExamService) SetGlobalAnswers(answers []AnswerItem) {
-	globalAnswers = answers
+// SetGlobalAnswers 设置全局答案数据，替换本地的默认题库，其他题库不受影响
+func (e *ExamService) SetGlobalAnswers(answers []AnswerItem) error {
+	_, err := answerStore.ReplaceDefaultBank(answers)
+	return err
 }
 
-// GetGlobalAnswers 获取全局答案数据
+// GetGlobalAnswers 获取所有启用题库中的答案数据
 func (e *ExamService) GetGlobalAnswers() []AnswerItem {
-	return globalAnswers
+	return answerStore.Answers()
 }
 
 // SearchRequest HTTP搜索请求结构
 type SearchRequest struct {
-	Query   string        `json:"query"`
-	Filters SearchFilters `json:"filters"`
-}
+	Query   string          `json:"query"`
+	Filters SearchFilters   `json:"filters"`
+	Mode    string          `json:"mode,omitempty"`   // 搜索模式：text（默认）或 options（按题干和选项组合搜索）
+	Parsed  *ParsedQuestion `json:"parsed,omitempty"` // 结构化题目，提供时忽略 query 和 mode
 
-type SearchFilters struct {
-	AccuracyFilters AccuracyFilters `json:"accuracyFilters"`
+	OCRConfidence float64 `json:"ocrConfidence,omitempty"` // 查询文本的OCR平均置信度，原样标注在搜索结果中
 }
 
 // SearchResponse HTTP搜索响应结构
@@ -1541,13 +1350,18 @@ type ParseCSVRequest struct {
 	Encoding        string `json:"encoding"`
 	OptionSeparator string `json:"optionSeparator"`
 	AnswerSeparator string `json:"answerSeparator"`
+	Mapping         string `json:"mapping"` // 列映射预设名称
+	Lenient         bool   `json:"lenient"` // 宽松模式：跳过有问题的行并返回诊断报告
 }
 
 // ParseCSVResponse HTTP CSV解析响应结构
 type ParseCSVResponse struct {
-	Success bool         `json:"success"`
-	Message string       `json:"message,omitempty"`
-	Results []AnswerItem `json:"results,omitempty"`
+	Success  bool              `json:"success"`
+	Message  string            `json:"message,omitempty"`
+	Results  []AnswerItem      `json:"results,omitempty"`
+	Report   *ImportReport     `json:"report,omitempty"`
+	Settings *DetectedSettings `json:"settings,omitempty"` // 实际使用的编码和分隔符
+	Name     string            `json:"name,omitempty"`     // 题库文件中记录的题库名称
 }
 
 // SetGlobalAnswersRequest HTTP设置全局答案请求结构
@@ -1597,8 +1411,14 @@ func handleParseCSV(w http.ResponseWriter, r *http.Request) {
 	// 创建ExamService实例
 	examService := &ExamService{}
 
-	// 调用ParseCSVFile方法
-	results, err := examService.ParseCSVFile(req.FilePath, req.Encoding, req.OptionSeparator, req.AnswerSeparator)
+	// 调用解析方法，宽松模式下附带诊断报告
+	results, report, settings, err := examService.parseCSV(req.FilePath, ImportOptions{
+		Encoding:        req.Encoding,
+		OptionSeparator: req.OptionSeparator,
+		AnswerSeparator: req.AnswerSeparator,
+		Mapping:         req.Mapping,
+		Lenient:         req.Lenient,
+	})
 	if err != nil {
 		response := ParseCSVResponse{
 			Success: false,
@@ -1611,8 +1431,10 @@ func handleParseCSV(w http.ResponseWriter, r *http.Request) {
 
 	// 返回解析结果
 	response := ParseCSVResponse{
-		Success: true,
-		Results: results,
+		Success:  true,
+		Results:  results,
+		Report:   report,
+		Settings: &settings,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -1650,7 +1472,16 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 
 	// 使用全局答案数据进行搜索
 	log.Printf("req %v", req)
-	results, err := examService.SearchAnswers(globalAnswers, req.Query, req.Filters.AccuracyFilters)
+	var results []SearchResult
+	var err error
+	switch {
+	case req.Parsed != nil:
+		results, err = examService.SearchParsedQuestion(*req.Parsed, req.Filters)
+	case req.Mode == SearchModeOptions:
+		results, err = examService.SearchByOptions(req.Query, req.Filters)
+	default:
+		results, err = examService.SearchBanks(req.Query, req.Filters)
+	}
 	if err != nil {
 		response := SearchResponse{
 			Success: false,
@@ -1662,6 +1493,9 @@ func handleSearch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 返回搜索结果
+	if req.OCRConfidence > 0 {
+		results = withOCRConfidence(results, req.OCRConfidence)
+	}
 	response := SearchResponse{
 		Success: true,
 		Results: results,
@@ -1701,7 +1535,15 @@ func handleSetGlobalAnswers(w http.ResponseWriter, r *http.Request) {
 	examService := &ExamService{}
 
 	// 调用SetGlobalAnswers方法
-	examService.SetGlobalAnswers(req.Answers)
+	if err := examService.SetGlobalAnswers(req.Answers); err != nil {
+		response := SetGlobalAnswersResponse{
+			Success: false,
+			Message: "设置全局答案失败: " + err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
 
 	// 返回设置结果
 	response := SetGlobalAnswersResponse{
@@ -1762,22 +1604,30 @@ type TestOCRResponse struct {
 
 // ScreenshotResponse HTTP截图响应结构
 type ScreenshotResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Image   string `json:"image,omitempty"`
+	Success   bool   `json:"success"`
+	Message   string `json:"message,omitempty"`
+	Image     string `json:"image,omitempty"`     // PNG格式的 data URL，请求参数 image=false 时不返回
+	CaptureID string `json:"captureId,omitempty"` // 缓存中的截图标识，可代替图片数据用于OCR，仅在 image=false 时返回
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
 }
 
 // PerformOCRRequest HTTP执行OCR请求结构
 type PerformOCRRequest struct {
 	Area   ScreenshotArea `json:"area"`
 	Config OCRConfig      `json:"config"`
+	Parse  bool           `json:"parse,omitempty"` // 是否同时返回结构化题目
 }
 
 // PerformOCRResponse HTTP执行OCR响应结构
 type PerformOCRResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Result  string `json:"result,omitempty"`
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Result  string          `json:"result,omitempty"`
+	Parsed  *ParsedQuestion `json:"parsed,omitempty"`
+	Image   string          `json:"image,omitempty"` // 预处理后交给OCR引擎的图片，仅在 config.debug 时返回
+
+	Confidence float64 `json:"confidence,omitempty"` // 识别文字的平均置信度，低置信度字符已替换为 □
 }
 
 // handleTestOCR 处理HTTP OCR测试请求
@@ -1853,8 +1703,16 @@ func handleTakeScreenshot(w http.ResponseWriter, r *http.Request) {
 	// 创建ExamService实例
 	examService := &ExamService{}
 
-	// 调用TakeScreenshotWithWindowControl方法
-	image, err := examService.TakeScreenshotWithWindowControl()
+	// 截取 display 参数指定的显示器，未指定时截取主显示器
+	displayID := r.URL.Query().Get("display")
+	img, err := examService.takeScreenshotWithWindowControl(displayID, "")
+
+	// 默认返回图片数据；image=false 时缓存截图并只返回截图标识，避免同一张截图既传输又占用缓存
+	var dataURL string
+	cached := r.URL.Query().Get("image") == "false"
+	if err == nil && !cached {
+		dataURL, err = imageDataURL(img)
+	}
 	if err != nil {
 		response := ScreenshotResponse{
 			Success: false,
@@ -1865,10 +1723,15 @@ func handleTakeScreenshot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 返回截图结果
 	response := ScreenshotResponse{
 		Success: true,
-		Image:   image,
+		Image:   dataURL,
+		Width:   img.Bounds().Dx(),
+		Height:  img.Bounds().Dy(),
+	}
+	if cached {
+		// 缓存截图，后续请求可按标识引用
+		response.CaptureID = captureCache.Put(img, displayID).ID
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -1904,8 +1767,8 @@ func handlePerformOCR(w http.ResponseWriter, r *http.Request) {
 	// 创建ExamService实例
 	examService := &ExamService{}
 
-	// 调用PerformOCR方法
-	result, err := examService.PerformOCR(req.Area, req.Config)
+	// 执行OCR，需要结构化题目时解析识别结果
+	run, err := examService.runOCR(req.Area, req.Config)
 	if err != nil {
 		response := PerformOCRResponse{
 			Success: false,
@@ -1918,8 +1781,17 @@ func handlePerformOCR(w http.ResponseWriter, r *http.Request) {
 
 	// 返回OCR结果
 	response := PerformOCRResponse{
-		Success: true,
-		Result:  result,
+		Success:    true,
+		Result:     joinOCRText(run.results),
+		Confidence: run.confidence,
+	}
+	if req.Parse {
+		parsed := ParseOCRResults(run.results)
+		parsed.Confidence = run.confidence
+		response.Result, response.Parsed = parsed.Text, &parsed
+	}
+	if req.Config.Debug {
+		response.Image = pngDataURL(run.image)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	_ "golang.org/x/image/webp"
+)
+
+// 截图缓存容量和有效期
+const (
+	maxCachedCaptures = 8
+	captureTTL        = 10 * time.Minute
+)
+
+// Capture 缓存中的截图，HTTP和前端接口通过 ID 引用截图，不再传递图片数据
+type Capture struct {
+	ID        string `json:"id"`                  // 截图标识
+	DisplayID string `json:"displayId,omitempty"` // 截取的显示器，为空时表示主显示器
+	Width     int    `json:"width"`               // 宽度
+	Height    int    `json:"height"`              // 高度
+}
+
+// cachedCapture 缓存项
+type cachedCapture struct {
+	Capture
+	image   image.Image
+	created time.Time
+}
+
+// ImageCache 截图缓存，超过容量时淘汰最早的截图
+type ImageCache struct {
+	mu       sync.Mutex
+	captures []cachedCapture // 按加入时间排序
+}
+
+// 全局截图缓存
+var captureCache = &ImageCache{}
+
+// Put 缓存截图，返回截图信息
+func (c *ImageCache) Put(img image.Image, displayID string) Capture {
+	capture := Capture{
+		ID:        uuid.NewString(),
+		DisplayID: displayID,
+		Width:     img.Bounds().Dx(),
+		Height:    img.Bounds().Dy(),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire()
+	if len(c.captures) >= maxCachedCaptures {
+		c.captures = c.captures[len(c.captures)-maxCachedCaptures+1:]
+	}
+	c.captures = append(c.captures, cachedCapture{Capture: capture, image: img, created: time.Now()})
+	return capture
+}
+
+// Get 按标识获取截图
+func (c *ImageCache) Get(id string) (image.Image, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire()
+	for _, capture := range c.captures {
+		if capture.ID == id {
+			return capture.image, nil
+		}
+	}
+	return nil, fmt.Errorf("截图不存在或已过期: %s", id)
+}
+
+// Delete 删除截图
+func (c *ImageCache) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, capture := range c.captures {
+		if capture.ID == id {
+			c.captures = append(c.captures[:i], c.captures[i+1:]...)
+			return
+		}
+	}
+}
+
+// expire 淘汰过期的截图，调用方需持有锁
+func (c *ImageCache) expire() {
+	i := 0
+	for i < len(c.captures) && time.Since(c.captures[i].created) > captureTTL {
+		i++
+	}
+	c.captures = c.captures[i:]
+}
+
+// decodeImage 解码PNG、JPEG或WebP图片
+func decodeImage(imageData []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(imageData))
+	if err != nil {
+		return nil, fmt.Errorf("图片解码失败: %v", err)
+	}
+	return img, nil
+}
+
+// decodeImageString 解码 data URL 或纯base64编码的图片
+func decodeImageString(data string) (image.Image, error) {
+	if strings.HasPrefix(data, "data:") {
+		data = data[strings.Index(data, ",")+1:]
+	}
+	imageData, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("图片解码失败: %v", err)
+	}
+	return decodeImage(imageData)
+}
+
+// encodePNG 将图片编码为PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("图片编码失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// imageDataURL 将图片编码为PNG格式的 data URL
+func imageDataURL(img image.Image) (string, error) {
+	imageData, err := encodePNG(img)
+	if err != nil {
+		return "", err
+	}
+	return pngDataURL(imageData), nil
+}
+
+// source 返回截图区域引用的完整截图
+// 依次使用内存中的截图、缓存中的截图和 Image 字段中的图片数据
+func (a ScreenshotArea) source() (image.Image, error) {
+	switch {
+	case a.screenshot != nil:
+		return a.screenshot, nil
+	case a.CaptureID != "":
+		return captureCache.Get(a.CaptureID)
+	case a.Image != "":
+		return decodeImageString(a.Image)
+	default:
+		return nil, fmt.Errorf("没有截图数据")
+	}
+}
+
+// cropImage 按截图区域裁剪图片，区域为空或超出图片范围时返回原图
+func cropImage(img image.Image, area ScreenshotArea) image.Image {
+	if area.Width <= 0 || area.Height <= 0 {
+		return img
+	}
+	bounds := img.Bounds()
+	if area.X+area.Width > bounds.Dx() || area.Y+area.Height > bounds.Dy() {
+		return img
+	}
+
+	rect := image.Rect(area.X, area.Y, area.X+area.Width, area.Y+area.Height).Add(bounds.Min)
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+	return img
+}
+
+// Capture 截取显示器并缓存，返回截图信息，displayID 的含义同 TakeDisplayScreenshot
+func (e *ExamService) Capture(displayID string) (Capture, error) {
+	img, err := e.captureDisplay(displayID, "")
+	if err != nil {
+		return Capture{}, err
+	}
+	return captureCache.Put(img, displayID), nil
+}
+
+// GetCaptureImage 获取缓存中的截图，按区域裁剪后以PNG格式的 data URL 返回，用于前端显示
+func (e *ExamService) GetCaptureImage(id string, area ScreenshotArea) (string, error) {
+	img, err := captureCache.Get(id)
+	if err != nil {
+		return "", err
+	}
+	return imageDataURL(cropImage(img, area))
+}
+
+// ReleaseCapture 从缓存中删除截图
+func (e *ExamService) ReleaseCapture(id string) {
+	captureCache.Delete(id)
+}
+
+// handleCaptureImage 处理HTTP获取截图请求
+// GET 按 id 参数返回PNG图片，可用 x、y、width、height 参数裁剪；DELETE 从缓存中删除截图
+func handleCaptureImage(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	query := r.URL.Query()
+	id := query.Get("id")
+
+	switch r.Method {
+	case "GET":
+	case "DELETE":
+		captureCache.Delete(id)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+		return
+	default:
+		http.Error(w, "只支持GET和DELETE方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	img, err := captureCache.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	area := ScreenshotArea{}
+	area.X, _ = strconv.Atoi(query.Get("x"))
+	area.Y, _ = strconv.Atoi(query.Get("y"))
+	area.Width, _ = strconv.Atoi(query.Get("width"))
+	area.Height, _ = strconv.Atoi(query.Get("height"))
+
+	imageData, err := encodePNG(cropImage(img, area))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(imageData)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	Encoding        string `json:"encoding"`        // 文件编码，auto 为自动检测（仅CSV）
+	OptionSeparator string `json:"optionSeparator"` // 选项分隔符，auto 为自动检测
+	AnswerSeparator string `json:"answerSeparator"` // 答案分隔符，auto 为自动检测
+	SheetName       string `json:"sheetName"`       // 工作表名称，为空时读取第一个工作表（仅Excel）
+	Mapping         string `json:"mapping"`         // 列映射预设名称，为空时使用默认映射
+	Lenient         bool   `json:"lenient"`         // 宽松模式：跳过有问题的行并返回诊断报告
+}
+
+// rowSource 逐行读取表格数据
+type rowSource interface {
+	// Read 读取下一行，返回行内容和行号；没有更多数据时返回 io.EOF
+	Read() ([]string, int, error)
+}
+
+// csvRows 从CSV读取表格数据
+type csvRows struct {
+	reader *csv.Reader
+}
+
+func (c *csvRows) Read() ([]string, int, error) {
+	record, err := c.reader.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+	row, _ := c.reader.FieldPos(0)
+	return record, row, nil
+}
+
+// bufferedRows 先返回缓存的行，再从底层数据源读取
+type bufferedRows struct {
+	buffered []bufferedRow
+	source   rowSource
+}
+
+// bufferedRow 缓存的一行数据
+type bufferedRow struct {
+	record []string
+	row    int
+	err    error
+}
+
+func (b *bufferedRows) Read() ([]string, int, error) {
+	if len(b.buffered) > 0 {
+		next := b.buffered[0]
+		b.buffered = b.buffered[1:]
+		return next.record, next.row, next.err
+	}
+	return b.source.Read()
+}
+
+// parseTable 解析表格数据为答案项
+// checkColumnCount 为 true 时在宽松模式下检查每行列数；settings 用于记录实际使用的分隔符
+func (e *ExamService) parseTable(source rowSource, options ImportOptions, checkColumnCount bool, settings *DetectedSettings) ([]AnswerItem, *ImportReport, error) {
+	var answers []AnswerItem
+
+	mapping, err := mappingStore.Get(options.Mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 读取标题行
+	headers, _, err := source.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("读取标题行失败: 文件为空")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取标题行失败: %v", err)
+	}
+
+	layout, err := resolveColumns(headers, mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 根据样本数据推测选项和答案分隔符
+	optionSeparator, answerSeparator := options.OptionSeparator, options.AnswerSeparator
+	if optionSeparator == autoSetting || answerSeparator == autoSetting {
+		buffered := &bufferedRows{source: source}
+		samples := [][]string{}
+		for len(buffered.buffered) < 50 {
+			record, row, err := source.Read()
+			if err == io.EOF {
+				break
+			}
+			buffered.buffered = append(buffered.buffered, bufferedRow{record: record, row: row, err: err})
+			if err == nil {
+				samples = append(samples, record)
+			}
+		}
+		source = buffered
+
+		proposedOption, proposedAnswer := e.proposeSeparators(samples, layout)
+		if optionSeparator == autoSetting {
+			optionSeparator = proposedOption
+		}
+		if answerSeparator == autoSetting {
+			answerSeparator = proposedAnswer
+		}
+	}
+	if settings != nil {
+		settings.OptionSeparator = optionSeparator
+		settings.AnswerSeparator = answerSeparator
+	}
+
+	var report *ImportReport
+	if options.Lenient {
+		report = newImportReport(layout)
+	}
+
+	columnCount := 0
+	if checkColumnCount {
+		columnCount = len(headers)
+	}
+
+	// 读取数据行
+	for {
+		record, row, err := source.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if !options.Lenient {
+				return nil, nil, fmt.Errorf("读取数据失败: %v", err)
+			}
+			report.addParseError(err)
+			continue
+		}
+
+		// 跳过空行
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		answer := e.buildAnswerItem(record, layout, optionSeparator, answerSeparator)
+		if options.Lenient {
+			splitOptions := optionSeparator != "" || len(layout.optionColumns) > 0
+			if !report.check(row, record, columnCount, answer, splitOptions) {
+				continue
+			}
+		}
+
+		answers = append(answers, answer)
+	}
+
+	return answers, report, nil
+}
+
+// ImportFile 按扩展名解析CSV、Excel或JSON、JSONL、YAML题库文件
+func (e *ExamService) ImportFile(filePath string, options ImportOptions) (ImportResult, error) {
+	var answers []AnswerItem
+	var report *ImportReport
+	var settings DetectedSettings
+	var err error
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".json", ".jsonl", ".ndjson", ".yaml", ".yml":
+		doc, report, err := e.parseBankFile(filePath, options)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		return ImportResult{Answers: doc.Answers, Report: report, Name: doc.Name}, nil
+	case ".xlsx", ".xlsm", ".xls":
+		answers, report, settings, err = e.parseExcel(filePath, options)
+	default:
+		answers, report, settings, err = e.parseCSV(filePath, options)
+	}
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return ImportResult{Answers: answers, Report: report, Settings: &settings}, nil
+}
+
+// ImportFileRequest HTTP导入文件请求结构
+type ImportFileRequest struct {
+	FilePath string        `json:"filePath"`
+	Options  ImportOptions `json:"options"`
+}
+
+// handleImportFile 处理HTTP导入文件请求
+func handleImportFile(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req ImportFileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	result, err := examService.ImportFile(req.FilePath, req.Options)
+	if err != nil {
+		response := ParseCSVResponse{
+			Success: false,
+			Message: "文件解析失败: " + err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := ParseCSVResponse{
+		Success:  true,
+		Results:  result.Answers,
+		Report:   result.Report,
+		Settings: result.Settings,
+		Name:     result.Name,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
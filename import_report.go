@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 导入问题类型
+const (
+	IssueParseError    = "parse_error"    // 行格式错误（如引号不匹配）
+	IssueColumnCount   = "column_count"   // 列数与标题行不一致
+	IssueEmptyQuestion = "empty_question" // 题目为空
+	IssueInvalidAnswer = "invalid_answer" // 答案字母不在选项范围内
+	IssueDuplicate     = "duplicate"      // 题目重复
+	IssueExtraColumn   = "extra_column"   // 标题行包含未使用的列
+	IssueMissingAnswer = "missing_answer" // 扫描件中未找到题目的答案
+	IssueOCRError      = "ocr_error"      // 扫描件页面识别失败
+)
+
+// ImportIssue 导入过程中发现的单行问题
+type ImportIssue struct {
+	Row     int    `json:"row"`     // 文件中的行号，从1开始，标题行为第1行；0表示与具体行无关
+	Kind    string `json:"kind"`    // 问题类型
+	Message string `json:"message"` // 问题描述
+}
+
+// ImportReport 导入诊断报告
+type ImportReport struct {
+	TotalRows    int           `json:"totalRows"`    // 数据行总数
+	ImportedRows int           `json:"importedRows"` // 成功导入的行数
+	SkippedRows  int           `json:"skippedRows"`  // 跳过的行数
+	Header       HeaderError   `json:"header"`       // 标题行校验结果
+	Issues       []ImportIssue `json:"issues"`       // 逐行问题列表
+
+	seen map[string]int // 题目 -> 首次出现的行号
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Answers  []AnswerItem      `json:"answers"`
+	Report   *ImportReport     `json:"report,omitempty"`   // 宽松模式下的诊断报告
+	Settings *DetectedSettings `json:"settings,omitempty"` // 实际使用的编码和分隔符
+	Name     string            `json:"name,omitempty"`     // 题库文件中记录的题库名称
+}
+
+// newImportReport 创建诊断报告并记录标题行中未映射的列
+func newImportReport(layout *columnLayout) *ImportReport {
+	report := &ImportReport{
+		Header: layout.header,
+		Issues: []ImportIssue{},
+		seen:   map[string]int{},
+	}
+	for _, extra := range report.Header.Extra {
+		report.Issues = append(report.Issues, ImportIssue{
+			Row:     1,
+			Kind:    IssueExtraColumn,
+			Message: fmt.Sprintf("未映射的列: %s，将作为额外信息保留", extra),
+		})
+	}
+	return report
+}
+
+// addIssue 记录一个问题
+func (r *ImportReport) addIssue(row int, kind string, format string, args ...any) {
+	r.Issues = append(r.Issues, ImportIssue{
+		Row:     row,
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// addParseError 记录无法解析的行
+func (r *ImportReport) addParseError(err error) {
+	r.TotalRows++
+	r.SkippedRows++
+
+	row := 0
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		row = parseErr.StartLine
+		err = parseErr.Err
+	}
+	r.addIssue(row, IssueParseError, "行格式错误: %v", err)
+}
+
+// check 校验一行数据，返回该行是否可以导入；columnCount 为0时不检查列数
+func (r *ImportReport) check(row int, record []string, columnCount int, answer AnswerItem, splitOptions bool) bool {
+	r.TotalRows++
+
+	ok := true
+	if columnCount > 0 && len(record) != columnCount {
+		r.addIssue(row, IssueColumnCount, "列数为%d，标题行为%d", len(record), columnCount)
+		ok = false
+	}
+
+	if answer.Question == "" {
+		r.addIssue(row, IssueEmptyQuestion, "题目为空")
+		ok = false
+	}
+
+	if splitOptions && !optionsEmpty(answer.Options) {
+		for _, ans := range answer.Answer {
+			idx, isLetter := maxAnswerLetterIndex(ans)
+			if isLetter && idx >= len(answer.Options) {
+				r.addIssue(row, IssueInvalidAnswer, "答案%s超出选项范围（共%d个选项）", strings.TrimSpace(ans), len(answer.Options))
+				ok = false
+			}
+		}
+	}
+
+	if answer.Question != "" {
+		if first, exists := r.seen[answer.Question]; exists {
+			r.addIssue(row, IssueDuplicate, "题目与第%d行重复", first)
+			ok = false
+		} else if ok {
+			r.seen[answer.Question] = row
+		}
+	}
+
+	if ok {
+		r.ImportedRows++
+	} else {
+		r.SkippedRows++
+	}
+	return ok
+}
+
+// maxAnswerLetterIndex 返回字母答案对应的最大选项下标，如 "B" -> 1，未拆分的 "ABE" -> 4
+// 全角字母先转换为半角，字母之间可以有空格或逗号等分隔符；
+// 答案不是同一大小写的字母序列（如 "正确"、"Yes"）时返回 false
+func maxAnswerLetterIndex(answer string) (int, bool) {
+	maxIndex, upper, lower := -1, false, false
+	for _, c := range answer {
+		c = foldRune(c)
+		switch {
+		case c >= 'A' && c <= 'Z':
+			maxIndex, upper = max(maxIndex, int(c-'A')), true
+		case c >= 'a' && c <= 'z':
+			maxIndex, lower = max(maxIndex, int(c-'a')), true
+		case strings.ContainsRune(" \t,、;", c):
+		default:
+			return 0, false
+		}
+	}
+	if maxIndex < 0 || (upper && lower) {
+		return 0, false
+	}
+	return maxIndex, true
+}
+
+// optionsEmpty 判断选项是否全部为空（如判断题）
+func optionsEmpty(options []string) bool {
+	for _, option := range options {
+		if strings.TrimSpace(option) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestMaxAnswerLetterIndex(t *testing.T) {
+	tests := []struct {
+		answer string
+		index  int
+		ok     bool
+	}{
+		{"B", 1, true},
+		{" c ", 2, true},
+		{"ABE", 4, true},
+		{"A、C，D", 3, true},
+		{"Ｃ", 2, true},
+		{"ＡＢＥ", 4, true},
+		{"正确", 0, false},
+		{"Yes", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		index, ok := maxAnswerLetterIndex(tt.answer)
+		if index != tt.index || ok != tt.ok {
+			t.Errorf("%q: 得到 %d %v，应为 %d %v", tt.answer, index, ok, tt.index, tt.ok)
+		}
+	}
+}
+
+func TestCheckAnswerOutOfRange(t *testing.T) {
+	for _, ans := range []string{"ABE", "Ｅ"} {
+		report := &ImportReport{Issues: []ImportIssue{}, seen: map[string]int{}}
+		answer := AnswerItem{Question: "以下属于安全色的是", Options: []string{"红色", "黄色", "蓝色", "粉色"}, Answer: []string{ans}}
+		if report.check(2, nil, 0, answer, true) {
+			t.Errorf("%q 超出选项范围，应跳过该行", ans)
+		}
+		if len(report.Issues) != 1 || report.Issues[0].Kind != IssueInvalidAnswer {
+			t.Errorf("%q: 诊断问题为%+v", ans, report.Issues)
+		}
+	}
+}
@@ -62,6 +62,11 @@ func main() {
 		URL:              "/",
 	})
 
+	// 加载本地题库
+	loadAnswerStore()
+	loadUserDictionaries()
+	loadConfusables()
+
 	// 启动HTTP服务器
 	go startHTTPServer()
 
@@ -85,21 +90,67 @@ func startHTTPServer() {
 	// 注册CSV解析接口
 	mux.HandleFunc("/api/parse-csv", handleParseCSV)
 
+	// 注册通用文件导入接口
+	mux.HandleFunc("/api/import-file", handleImportFile)
+	mux.HandleFunc("/api/import-scans", handleImportScans)
+
+	// 注册用户词典接口
+	mux.HandleFunc("/api/user-dictionary", handleUserDictionary)
+
+	// 注册易混淆字符接口
+	mux.HandleFunc("/api/confusables", handleConfusables)
+
+	// 注册列映射预设接口
+	mux.HandleFunc("/api/column-mappings", handleColumnMappings)
+
+	// 注册导入设置检测接口
+	mux.HandleFunc("/api/detect-settings", handleDetectSettings)
+
+	// 注册Excel解析接口
+	mux.HandleFunc("/api/parse-excel", handleParseExcel)
+
 	// 注册设置全局答案接口
 	mux.HandleFunc("/api/set-global-answers", handleSetGlobalAnswers)
 
 	// 注册获取全局答案接口
 	mux.HandleFunc("/api/get-global-answers", handleGetGlobalAnswers)
 
+	// 注册题库管理接口
+	mux.HandleFunc("/api/banks", handleListBanks)
+	mux.HandleFunc("/api/import-bank", handleImportBank)
+	mux.HandleFunc("/api/export-bank", handleExportBank)
+	mux.HandleFunc("/api/rename-bank", handleUpdateBank("rename"))
+	mux.HandleFunc("/api/delete-bank", handleUpdateBank("delete"))
+	mux.HandleFunc("/api/toggle-bank", handleUpdateBank("toggle"))
+
 	// 注册OCR测试接口
 	mux.HandleFunc("/api/test-ocr", handleTestOCR)
 
 	// 注册截图接口
 	mux.HandleFunc("/api/take-screenshot", handleTakeScreenshot)
 
+	// 注册截图缓存接口
+	mux.HandleFunc("/api/capture-image", handleCaptureImage)
+
+	// 注册显示器列表接口
+	mux.HandleFunc("/api/displays", handleListDisplays)
+
+	// 注册截图诊断接口
+	mux.HandleFunc("/api/screenshot-diagnostics", handleScreenshotDiagnostics)
+
 	// 注册执行OCR接口
 	mux.HandleFunc("/api/perform-ocr", handlePerformOCR)
 
+	// 注册截图区域接口
+	mux.HandleFunc("/api/capture-regions", handleCaptureRegions)
+
+	// 注册截图、识别并搜索接口
+	mux.HandleFunc("/api/capture-search", handleCaptureSearch)
+
+	// 注册截图区域监视接口
+	mux.HandleFunc("/api/watch", handleWatch)
+	mux.HandleFunc("/api/watch/stream", handleWatchStream)
+
 	// 启动服务器
 	port := ":8088"
 	log.Printf("HTTP服务器启动在端口 %s", port)
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wildcardRune 低置信度字符的占位符，搜索时可匹配任意字符
+const wildcardRune = '□'
+
+// 默认置信度阈值
+const (
+	defaultMinConfidence      = 0.3 // 整体置信度低于此值的识别结果被丢弃
+	defaultWildcardConfidence = 0.6 // 置信度低于此值的字符替换为通配符
+)
+
+// confidenceThresholds 返回配置的丢弃阈值和通配符阈值，未配置时使用默认值
+func (c OCRConfig) confidenceThresholds() (float64, float64) {
+	minConfidence, wildcardConfidence := c.MinConfidence, c.WildcardConfidence
+	if minConfidence == 0 {
+		minConfidence = defaultMinConfidence
+	}
+	if wildcardConfidence == 0 {
+		wildcardConfidence = defaultWildcardConfidence
+	}
+	return minConfidence, wildcardConfidence
+}
+
+// runeConfidences 返回识别结果每个字符的置信度，引擎未提供置信度时返回 nil
+// 没有逐字置信度时每个字符使用整体置信度
+func runeConfidences(result OCRResult) []float64 {
+	runes := []rune(result.Text)
+	if len(result.CharConfidences) == len(runes) && len(runes) > 0 {
+		return result.CharConfidences
+	}
+	if result.Confidence <= 0 {
+		return nil
+	}
+
+	confidences := make([]float64, len(runes))
+	for i := range confidences {
+		confidences[i] = result.Confidence
+	}
+	return confidences
+}
+
+// applyOCRConfidence 丢弃整体置信度过低的识别结果，并将置信度低的字符替换为通配符
+// 只有引擎提供逐字置信度时才替换通配符；只有整体置信度的识别结果保留原文，
+// 其置信度按字符数计入平均置信度，从而降低搜索结果中标注的OCR置信度
+// 返回处理后的识别结果，以及按字符加权的平均置信度（引擎未提供置信度时为0）
+func applyOCRConfidence(results []OCRResult, config OCRConfig) ([]OCRResult, float64) {
+	minConfidence, wildcardConfidence := config.confidenceThresholds()
+
+	kept := make([]OCRResult, 0, len(results))
+	total, count := 0.0, 0
+	for _, result := range results {
+		confidences := runeConfidences(result)
+		if confidences == nil {
+			kept = append(kept, result)
+			continue
+		}
+		if result.Confidence > 0 && result.Confidence < minConfidence {
+			continue
+		}
+
+		runes := []rune(result.Text)
+		perRune := len(result.CharConfidences) == len(runes)
+		for i, r := range runes {
+			if unicode.IsSpace(r) {
+				continue
+			}
+			total += confidences[i]
+			count++
+			if perRune && confidences[i] < wildcardConfidence {
+				runes[i] = wildcardRune
+			}
+		}
+		result.Text = string(runes)
+
+		// 全部字符都是通配符的结果对搜索没有帮助
+		if strings.Trim(result.Text, string(wildcardRune)+" ") == "" {
+			continue
+		}
+		kept = append(kept, result)
+	}
+
+	if count == 0 {
+		return kept, 0
+	}
+	return kept, total / float64(count)
+}
+
+// wildcardIndex 查找 query 在 text 中首次连续出现的字符位置，query 中的通配符可匹配任意字符
+// 找不到时返回 -1
+func wildcardIndex(text, query []rune) int {
+	for start := 0; start+len(query) <= len(text); start++ {
+		matched := true
+		for i, r := range query {
+			if r != wildcardRune && r != text[start+i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return start
+		}
+	}
+	return -1
+}
+
+// withOCRConfidence 在搜索结果中标注查询文本的OCR置信度
+func withOCRConfidence(results []SearchResult, confidence float64) []SearchResult {
+	for i := range results {
+		results[i].OCRConfidence = confidence
+	}
+	return results
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// OCR模式，对应 OCRConfig.Mode
+const (
+	OCRModeLocal     = "local"     // 本地 /ocr JSON 服务（默认）
+	OCRModeOnline    = "online"    // OCR.space 风格的在线识别接口
+	OCRModeTesseract = "tesseract" // 本机安装的 tesseract 命令行
+)
+
+// mockOCRText 本地模式未配置服务地址时返回的模拟识别结果
+const mockOCRText = "这是一个模拟的OCR识别结果"
+
+// OCREngine OCR识别引擎
+type OCREngine interface {
+	// Recognize 识别PNG图片中的文字，返回带位置信息的识别结果
+	Recognize(imageData []byte, config OCRConfig) ([]OCRResult, error)
+	// Check 检查引擎是否可用
+	Check(config OCRConfig) error
+}
+
+var (
+	ocrEngineMu sync.RWMutex
+	// 已注册的OCR引擎
+	ocrEngines = map[string]OCREngine{
+		OCRModeLocal:     &localOCREngine{},
+		OCRModeOnline:    &onlineOCREngine{},
+		OCRModeTesseract: &tesseractOCREngine{},
+	}
+)
+
+// RegisterOCREngine 注册OCR引擎，同名引擎会被替换
+func RegisterOCREngine(mode string, engine OCREngine) {
+	ocrEngineMu.Lock()
+	defer ocrEngineMu.Unlock()
+	ocrEngines[mode] = engine
+}
+
+// ocrEngineFor 返回配置的模式对应的OCR引擎，未指定模式时使用本地服务
+func ocrEngineFor(config OCRConfig) (OCREngine, error) {
+	mode := config.Mode
+	if mode == "" {
+		mode = OCRModeLocal
+	}
+
+	ocrEngineMu.RLock()
+	defer ocrEngineMu.RUnlock()
+	engine, ok := ocrEngines[mode]
+	if !ok {
+		return nil, fmt.Errorf("不支持的OCR模式: %s", mode)
+	}
+	return engine, nil
+}
+
+// ListOCREngines 获取所有已注册的OCR模式
+func (e *ExamService) ListOCREngines() []string {
+	ocrEngineMu.RLock()
+	defer ocrEngineMu.RUnlock()
+
+	modes := make([]string, 0, len(ocrEngines))
+	for mode := range ocrEngines {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// recognize 使用配置的OCR引擎识别图片
+func (e *ExamService) recognize(imageData []byte, config OCRConfig) ([]OCRResult, error) {
+	engine, err := ocrEngineFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return engine.Recognize(imageData, config)
+}
+
+// joinOCRText 只保留文字内容，以空格合并所有识别结果
+func joinOCRText(results []OCRResult) string {
+	var allText strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			allText.WriteString(" ")
+		}
+		allText.WriteString(strings.TrimSpace(result.Text))
+	}
+	return allText.String()
+}
+
+// wordSeparator 返回OCR引擎逐词输出时连接相邻两词的分隔符
+// 引擎会把中文拆成单字或短词，中文之间直接相连，其余情况以空格分隔
+func wordSeparator(before, after string) string {
+	if before == "" {
+		return ""
+	}
+	last, _ := utf8.DecodeLastRuneInString(before)
+	first, _ := utf8.DecodeRuneInString(after)
+	if unicode.Is(unicode.Han, last) || unicode.Is(unicode.Han, first) {
+		return ""
+	}
+	return " "
+}
+
+// serviceURL 在服务地址后拼接接口路径
+func serviceURL(base, path string) string {
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base + path
+}
+
+// localOCREngine 本地 /ocr JSON 服务，请求体为 {"image": base64}，响应为 OCRResponse
+type localOCREngine struct {
+	Client *http.Client
+}
+
+// client 返回发送请求使用的HTTP客户端
+func (l *localOCREngine) client(timeout time.Duration) *http.Client {
+	if l.Client != nil {
+		return l.Client
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// Recognize 将图片发送到 /ocr 接口识别，未配置服务地址时返回模拟结果
+func (l *localOCREngine) Recognize(imageData []byte, config OCRConfig) ([]OCRResult, error) {
+	if config.URL == "" {
+		return []OCRResult{{Text: mockOCRText}}, nil
+	}
+
+	// 准备请求数据
+	requestData := map[string]string{
+		"image": base64.StdEncoding.EncodeToString(imageData),
+	}
+
+	jsonData, err := json.Marshal(requestData)
+	if err != nil {
+		return nil, fmt.Errorf("编码请求数据失败: %v", err)
+	}
+
+	// 发送HTTP请求
+	req, err := http.NewRequest("POST", serviceURL(config.URL, "ocr"), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("创建OCR请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client(30 * time.Second).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送OCR请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取OCR响应失败: %v", err)
+	}
+
+	// 解析JSON响应
+	var ocrResp OCRResponse
+	err = json.Unmarshal(body, &ocrResp)
+	if err != nil {
+		return nil, fmt.Errorf("解析OCR响应失败: %v", err)
+	}
+
+	if !ocrResp.Success {
+		return nil, fmt.Errorf("OCR服务返回错误")
+	}
+
+	return ocrResp.Data.Results, nil
+}
+
+// Check 请求 /health 接口检查服务状态
+func (l *localOCREngine) Check(config OCRConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("未配置OCR服务URL")
+	}
+
+	// 发送HTTP请求到健康检查端点
+	req, err := http.NewRequest("GET", serviceURL(config.URL, "health"), nil)
+	if err != nil {
+		return fmt.Errorf("创建健康检查请求失败: %v", err)
+	}
+
+	resp, err := l.client(10 * time.Second).Do(req)
+	if err != nil {
+		return fmt.Errorf("健康检查请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取健康检查响应失败: %v", err)
+	}
+
+	// 检查HTTP状态码
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("健康检查失败，状态码: %d", resp.StatusCode)
+	}
+
+	// 尝试解析JSON响应，无法解析但状态码是200时也认为连接成功
+	var healthResp struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &healthResp); err == nil && !healthResp.Success {
+		return fmt.Errorf("OCR服务报告错误: %s", healthResp.Message)
+	}
+	return nil
+}
+
+// onlineOCREngine OCR.space 风格的在线识别接口，以 multipart 表单上传图片
+type onlineOCREngine struct{}
+
+// onlineOCRResponse 在线识别接口的响应结构
+type onlineOCRResponse struct {
+	ParsedResults []struct {
+		ParsedText  string `json:"ParsedText"`
+		TextOverlay struct {
+			Lines []struct {
+				Words []struct {
+					WordText string  `json:"WordText"`
+					Left     float64 `json:"Left"`
+					Top      float64 `json:"Top"`
+					Height   float64 `json:"Height"`
+					Width    float64 `json:"Width"`
+				} `json:"Words"`
+			} `json:"Lines"`
+		} `json:"TextOverlay"`
+	} `json:"ParsedResults"`
+	IsErroredOnProcessing bool `json:"IsErroredOnProcessing"`
+	ErrorMessage          any  `json:"ErrorMessage"` // 可能是字符串或字符串数组
+}
+
+// Recognize 上传图片到在线接口识别，每个文本行作为一个识别结果
+func (o *onlineOCREngine) Recognize(imageData []byte, config OCRConfig) ([]OCRResult, error) {
+	if err := o.Check(config); err != nil {
+		return nil, err
+	}
+
+	// 创建multipart表单
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// 添加文件
+	part, err := writer.CreateFormFile("file", "screenshot.png")
+	if err != nil {
+		return nil, fmt.Errorf("创建表单失败: %v", err)
+	}
+	_, err = part.Write(imageData)
+	if err != nil {
+		return nil, fmt.Errorf("写入图片数据失败: %v", err)
+	}
+
+	// 添加其他参数，需要文本位置时开启 isOverlayRequired
+	fields := [][2]string{
+		{"apikey", config.APIKey},
+		{"language", "chs"},
+		{"isOverlayRequired", "true"},
+		{"filetype", "png"},
+		{"detectOrientation", "true"},
+	}
+	for _, field := range fields {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return nil, fmt.Errorf("写入表单字段失败: %v", err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("关闭表单失败: %v", err)
+	}
+
+	// 发送HTTP请求
+	req, err := http.NewRequest("POST", config.URL, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %v", err)
+	}
+
+	// 解析JSON响应
+	var result onlineOCRResponse
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		return nil, fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	if result.IsErroredOnProcessing || (result.ErrorMessage != nil && result.ErrorMessage != "") {
+		return nil, fmt.Errorf("OCR服务错误: %v", result.ErrorMessage)
+	}
+
+	if len(result.ParsedResults) == 0 {
+		return nil, fmt.Errorf("没有识别到文本")
+	}
+
+	// 有文本位置时每行的外接矩形由各个词的位置合并得到，否则按行拆分识别文本
+	parsed := result.ParsedResults[0]
+	results := []OCRResult{}
+	for _, line := range parsed.TextOverlay.Lines {
+		var r OCRResult
+		for i, word := range line.Words {
+			left, top := int(word.Left), int(word.Top)
+			right, bottom := int(word.Left+word.Width), int(word.Top+word.Height)
+			if i == 0 {
+				r.BBox.XMin, r.BBox.YMin, r.BBox.XMax, r.BBox.YMax = left, top, right, bottom
+			} else {
+				r.Text += wordSeparator(r.Text, word.WordText)
+				r.BBox.XMin, r.BBox.YMin = min(r.BBox.XMin, left), min(r.BBox.YMin, top)
+				r.BBox.XMax, r.BBox.YMax = max(r.BBox.XMax, right), max(r.BBox.YMax, bottom)
+			}
+			r.Text += word.WordText
+		}
+		if r.Text != "" {
+			results = append(results, r)
+		}
+	}
+	if len(results) == 0 {
+		for _, line := range strings.Split(parsed.ParsedText, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				results = append(results, OCRResult{Text: line})
+			}
+		}
+	}
+	return results, nil
+}
+
+// Check 检查在线接口地址和API密钥是否已配置
+func (o *onlineOCREngine) Check(config OCRConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("未配置OCR服务URL")
+	}
+	if config.APIKey == "" {
+		return fmt.Errorf("未配置API密钥")
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParsedQuestion 从OCR结果中解析出的结构化题目
+type ParsedQuestion struct {
+	Number  string   `json:"number"`  // 题号
+	Type    string   `json:"type"`    // 题型提示：单选题、多选题或判断题
+	Stem    string   `json:"stem"`    // 题干
+	Options []string `json:"options"` // 选项文本，不含 A. 等标签
+	Text    string   `json:"text"`    // 按阅读顺序重建的完整文本，各行以换行分隔
+	Lines   []string `json:"lines"`   // 按阅读顺序重建的文本行
+
+	Confidence float64 `json:"confidence,omitempty"` // 识别文字的平均置信度，低置信度字符已替换为 □
+}
+
+// 题型提示
+const (
+	QuestionTypeSingle   = "单选题"
+	QuestionTypeMultiple = "多选题"
+	QuestionTypeJudge    = "判断题"
+)
+
+// typeMismatchPenalty 识别出的题型提示与题库题型不一致时的分数折算比例
+const typeMismatchPenalty = 0.9
+
+// questionNumberPattern 匹配题目开头的题号，如 1. 2、 (3) 第4题
+var questionNumberPattern = regexp.MustCompile(`^\s*(?:第\s*(\d{1,4})\s*题\s*[.．、:：]?|[（(]\s*(\d{1,4})\s*[)）]|(\d{1,4})\s*[.．、)）:：])\s*`)
+
+// questionTypePattern 匹配题目开头的题型提示，如 【单选题】 (多项选择题) 判断题： 多选： 以及独占一行或后跟空白的 单选题
+// 有左括号时题型之后须有“题”字或右括号、冒号；没有左括号时题型（及其后的“题”字）之后须紧跟右括号或冒号，
+// 或者“题”字之后为空白或文本结尾，以免把“判断题目所述内容”“判断下列说法”“多选择一些”等题干开头误认为题型
+var questionTypePattern = regexp.MustCompile(`^\s*(?:[\[【(（]\s*(单选|单项选择|多选|多项选择|不定项选择|不定项|判断)(?:题\s*[\]】)）:：]?|\s*[\]】)）:：])|(单选|单项选择|多选|多项选择|不定项选择|不定项|判断)(?:题?\s*[\]】)）:：]|题(?:\s+|$)))\s*`)
+
+// questionScorePattern 匹配题目开头的分值，如 (2分) 【2.5分】
+var questionScorePattern = regexp.MustCompile(`^\s*[\[【(（]\s*\d+(?:\.\d+)?\s*分\s*[\]】)）]\s*`)
+
+// ocrBox 带位置信息的一段识别文字
+type ocrBox struct {
+	text                   string
+	xMin, yMin, xMax, yMax int
+}
+
+// ocrLine 阅读顺序中的一行文字
+type ocrLine struct {
+	boxes      []ocrBox
+	yMin, yMax int
+}
+
+// boxBounds 返回识别结果的外接矩形，没有矩形时由多边形顶点计算
+func boxBounds(result OCRResult) (ocrBox, bool) {
+	box := ocrBox{
+		text: strings.TrimSpace(result.Text),
+		xMin: result.BBox.XMin,
+		yMin: result.BBox.YMin,
+		xMax: result.BBox.XMax,
+		yMax: result.BBox.YMax,
+	}
+	if box.yMax > box.yMin {
+		return box, true
+	}
+
+	valid := false
+	for _, p := range result.BBox.Points {
+		if len(p) < 2 {
+			continue
+		}
+		if !valid {
+			box.xMin, box.xMax, box.yMin, box.yMax = p[0], p[0], p[1], p[1]
+			valid = true
+			continue
+		}
+		box.xMin, box.xMax = min(box.xMin, p[0]), max(box.xMax, p[0])
+		box.yMin, box.yMax = min(box.yMin, p[1]), max(box.yMax, p[1])
+	}
+	return box, valid && box.yMax > box.yMin
+}
+
+// orderOCRLines 按识别框位置重建阅读顺序，返回自上而下的文本行
+// 垂直方向重叠超过较矮一方一半高度的识别框视为同一行，行内按从左到右排列；
+// 识别结果没有位置信息时按原顺序每个结果作为一行
+func orderOCRLines(results []OCRResult) []string {
+	boxes := make([]ocrBox, 0, len(results))
+	for _, result := range results {
+		box, ok := boxBounds(result)
+		if !ok {
+			return plainOCRLines(results)
+		}
+		if box.text != "" {
+			boxes = append(boxes, box)
+		}
+	}
+
+	sort.SliceStable(boxes, func(i, j int) bool {
+		return boxes[i].yMin+boxes[i].yMax < boxes[j].yMin+boxes[j].yMax
+	})
+
+	lines := []*ocrLine{}
+	for _, box := range boxes {
+		var target *ocrLine
+		for _, line := range lines {
+			overlap := min(line.yMax, box.yMax) - max(line.yMin, box.yMin)
+			if overlap*2 >= min(line.yMax-line.yMin, box.yMax-box.yMin) {
+				target = line
+				break
+			}
+		}
+		if target == nil {
+			lines = append(lines, &ocrLine{yMin: box.yMin, yMax: box.yMax})
+			target = lines[len(lines)-1]
+		}
+		target.boxes = append(target.boxes, box)
+		target.yMin, target.yMax = min(target.yMin, box.yMin), max(target.yMax, box.yMax)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].yMin+lines[i].yMax < lines[j].yMin+lines[j].yMax
+	})
+
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		sort.SliceStable(line.boxes, func(i, j int) bool {
+			return line.boxes[i].xMin < line.boxes[j].xMin
+		})
+
+		var text strings.Builder
+		for i, box := range line.boxes {
+			if i > 0 {
+				// 间隔超过半个字高时视为分栏（如同一行的多个选项），以空格分隔
+				prev := line.boxes[i-1]
+				if (box.xMin-prev.xMax)*2 > line.yMax-line.yMin {
+					text.WriteString(" ")
+				} else {
+					text.WriteString(fragmentSeparator(text.String(), box.text))
+				}
+			}
+			text.WriteString(box.text)
+		}
+		texts = append(texts, text.String())
+	}
+	return texts
+}
+
+// plainOCRLines 按原顺序将每个识别结果作为一行
+func plainOCRLines(results []OCRResult) []string {
+	lines := []string{}
+	for _, result := range results {
+		if text := strings.TrimSpace(result.Text); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return lines
+}
+
+// fragmentSeparator 返回连接两段文字时使用的分隔符
+// 两侧都是字母或数字时用空格分隔，避免英文单词粘连；中文直接相连
+func fragmentSeparator(before, after string) string {
+	last, _ := utf8.DecodeLastRuneInString(before)
+	first, _ := utf8.DecodeRuneInString(after)
+	if isLatinWordRune(last) && isLatinWordRune(first) {
+		return " "
+	}
+	return ""
+}
+
+// isLatinWordRune 判断字符是否属于英文单词或数字
+func isLatinWordRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// joinWrappedLines 将因换行被拆开的文字重新连接
+func joinWrappedLines(text string) string {
+	var joined strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if joined.Len() > 0 {
+			joined.WriteString(fragmentSeparator(joined.String(), line))
+		}
+		joined.WriteString(line)
+	}
+	return joined.String()
+}
+
+// questionTypeOf 将题型提示或题库中的题型归为单选题、多选题或判断题，无法识别时返回空字符串
+func questionTypeOf(hint string) string {
+	switch {
+	case strings.Contains(hint, "多"), strings.Contains(hint, "不定项"):
+		return QuestionTypeMultiple
+	case strings.Contains(hint, "单"):
+		return QuestionTypeSingle
+	case strings.Contains(hint, "判断"):
+		return QuestionTypeJudge
+	}
+	return ""
+}
+
+// ParseOCRResults 将OCR识别结果解析为结构化题目
+// 按识别框位置重建行和阅读顺序后，识别题号、题型提示、题干和选项
+func ParseOCRResults(results []OCRResult) ParsedQuestion {
+	return parseQuestionLines(orderOCRLines(results))
+}
+
+// ParseQuestionText 将已按行排列的识别文本解析为结构化题目
+func ParseQuestionText(text string) ParsedQuestion {
+	return parseQuestionLines(strings.Split(text, "\n"))
+}
+
+// parseQuestionLines 从阅读顺序的文本行中识别题号、题型提示、题干和选项
+func parseQuestionLines(lines []string) ParsedQuestion {
+	q := ParsedQuestion{Options: []string{}, Lines: []string{}}
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			q.Lines = append(q.Lines, line)
+		}
+	}
+	q.Text = strings.Join(q.Lines, "\n")
+
+	// 题号、题型提示和分值可能以任意顺序出现在开头，如 1.【单选题】(2分)
+	body := q.Text
+	for stripped := true; stripped; {
+		stripped = false
+		if q.Number == "" {
+			if m := questionNumberPattern.FindStringSubmatchIndex(body); m != nil && !followedByDigit(body, m[1]) {
+				for g := 1; g <= 3; g++ {
+					if m[2*g] >= 0 {
+						q.Number = body[m[2*g]:m[2*g+1]]
+					}
+				}
+				body, stripped = body[m[1]:], true
+			}
+		}
+		if q.Type == "" {
+			if m := questionTypePattern.FindStringSubmatchIndex(body); m != nil {
+				for g := 1; g <= 2; g++ {
+					if m[2*g] >= 0 {
+						q.Type = questionTypeOf(body[m[2*g]:m[2*g+1]])
+					}
+				}
+				body, stripped = body[m[1]:], true
+			}
+		}
+		if loc := questionScorePattern.FindStringIndex(body); loc != nil {
+			body, stripped = body[loc[1]:], true
+		}
+	}
+
+	stem, options := splitQuestionBlock(body)
+	q.Stem = joinWrappedLines(stem)
+	for _, option := range options {
+		q.Options = append(q.Options, joinWrappedLines(option))
+	}
+	return q
+}
+
+// followedByDigit 判断位置 i 处是否紧跟数字，用于排除 1.5 这类小数被误认为题号
+func followedByDigit(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return r >= '0' && r <= '9'
+}
+
+// PerformOCRParsed 执行OCR识别并解析为结构化题目
+func (e *ExamService) PerformOCRParsed(area ScreenshotArea, config OCRConfig) (ParsedQuestion, error) {
+	run, err := e.runOCR(area, config)
+	if err != nil {
+		return ParsedQuestion{}, err
+	}
+
+	q := ParseOCRResults(run.results)
+	q.Confidence = run.confidence
+	return q, nil
+}
+
+// SearchParsedQuestion 按结构化题目在所有启用的题库中搜索
+// 有选项时按题干与选项组合打分，否则按题干搜索；题型提示与题库题型不一致的结果分数略微降低
+func (e *ExamService) SearchParsedQuestion(q ParsedQuestion, filters SearchFilters) ([]SearchResult, error) {
+	var results []SearchResult
+	var err error
+	if len(q.Options) > 0 {
+		query := strings.Join(append([]string{q.Stem}, q.Options...), "\n")
+		results = e.searchStemAndOptions(q.Stem, q.Options, query, filters)
+	} else {
+		results, err = e.SearchBanks(q.Stem, filters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Confidence > 0 {
+		results = withOCRConfidence(results, q.Confidence)
+	}
+	if q.Type == "" {
+		return results, nil
+	}
+
+	filtered := results[:0]
+	for _, result := range results {
+		if itemType := questionTypeOf(result.Item.Type); itemType != "" && itemType != q.Type {
+			result.Score *= typeMismatchPenalty
+			if !filters.AccuracyFilters.accept(result.Score) {
+				continue
+			}
+		}
+		filtered = append(filtered, result)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Score > filtered[j].Score
+	})
+	return filtered, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestParseQuestionTypeHint(t *testing.T) {
+	tests := []struct {
+		text, number, typ, stem string
+	}{
+		{"判断题目所述内容是否正确", "", "", "判断题目所述内容是否正确"},
+		{"判断下列说法是否正确", "", "", "判断下列说法是否正确"},
+		{"【单选题】下列属于安全色的是", "", QuestionTypeSingle, "下列属于安全色的是"},
+		{"多选题：以下说法正确的是", "", QuestionTypeMultiple, "以下说法正确的是"},
+		{"(多项选择题)以下说法正确的是", "", QuestionTypeMultiple, "以下说法正确的是"},
+		{"1. 判断：安全帽属于个人防护用品", "1", QuestionTypeJudge, "安全帽属于个人防护用品"},
+		{"判断题 安全帽属于个人防护用品", "", QuestionTypeJudge, "安全帽属于个人防护用品"},
+		{"单选题\n1. 下列属于安全色的是\nA. 红色\nB. 黑色", "1", QuestionTypeSingle, "下列属于安全色的是"},
+	}
+
+	for _, tt := range tests {
+		q := ParseQuestionText(tt.text)
+		if q.Number != tt.number || q.Type != tt.typ || q.Stem != tt.stem {
+			t.Errorf("%s: 题号=%q 题型=%q 题干=%q，应为 题号=%q 题型=%q 题干=%q",
+				tt.text, q.Number, q.Type, q.Stem, tt.number, tt.typ, tt.stem)
+		}
+	}
+}
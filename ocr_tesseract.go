@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tesseract 默认配置
+const (
+	defaultTesseractPath      = "tesseract"
+	defaultTesseractLanguages = "chi_sim+eng"
+	tesseractTimeout          = 30 * time.Second
+)
+
+// tesseractPathEnv 指定 tesseract 可执行文件路径的环境变量，未设置时从 PATH 中查找
+// 路径只能在本机设置，不能随识别请求传入，以免HTTP接口的调用方借此执行任意程序
+const tesseractPathEnv = "EXAM_TESSERACT_PATH"
+
+// TesseractConfig tesseract 命令行配置
+type TesseractConfig struct {
+	Languages string `json:"languages,omitempty"` // 识别语言，多个语言以 + 连接，默认 chi_sim+eng
+	PSM       int    `json:"psm,omitempty"`       // 页面分割模式，0 表示使用 tesseract 的默认值
+}
+
+// UnmarshalJSON 解析 tesseract 配置，拒绝随请求传入的可执行文件路径
+func (c *TesseractConfig) UnmarshalJSON(data []byte) error {
+	type plain TesseractConfig
+	var config struct {
+		plain
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	if config.Path != "" {
+		return fmt.Errorf("不支持在请求中指定tesseract路径，请设置环境变量 %s", tesseractPathEnv)
+	}
+	*c = TesseractConfig(config.plain)
+	return nil
+}
+
+// tesseractExecutable 返回 tesseract 可执行文件路径，优先使用环境变量指定的路径
+func tesseractExecutable() (string, error) {
+	path := os.Getenv(tesseractPathEnv)
+	if path == "" {
+		path = defaultTesseractPath
+	}
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return "", fmt.Errorf("未找到tesseract: %v", err)
+	}
+	return resolved, nil
+}
+
+// languages 返回识别语言
+func (c TesseractConfig) languages() string {
+	if c.Languages != "" {
+		return c.Languages
+	}
+	return defaultTesseractLanguages
+}
+
+// tesseractOCREngine 调用本机安装的 tesseract 命令行识别
+type tesseractOCREngine struct{}
+
+// run 执行 tesseract 并返回标准输出
+func (t *tesseractOCREngine) run(stdin []byte, args ...string) ([]byte, error) {
+	path, err := tesseractExecutable()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tesseractTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("执行tesseract失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Recognize 通过标准输入把图片交给 tesseract，解析TSV输出，每个文本行作为一个识别结果
+func (t *tesseractOCREngine) Recognize(imageData []byte, config OCRConfig) ([]OCRResult, error) {
+	args := []string{"stdin", "stdout", "-l", config.Tesseract.languages()}
+	if config.Tesseract.PSM > 0 {
+		args = append(args, "--psm", strconv.Itoa(config.Tesseract.PSM))
+	}
+	args = append(args, "tsv")
+
+	output, err := t.run(imageData, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseTesseractTSV(bytes.NewReader(output))
+}
+
+// Check 检查 tesseract 是否可以执行，以及配置的语言包是否都已安装
+func (t *tesseractOCREngine) Check(config OCRConfig) error {
+	output, err := t.run(nil, "--list-langs")
+	if err != nil {
+		return err
+	}
+
+	// 第一行为说明文字，其余每行一个语言
+	installed := map[string]bool{}
+	for _, line := range strings.Split(string(output), "\n")[1:] {
+		installed[strings.TrimSpace(line)] = true
+	}
+	for _, lang := range strings.Split(config.Tesseract.languages(), "+") {
+		if !installed[lang] {
+			return fmt.Errorf("tesseract未安装语言包: %s", lang)
+		}
+	}
+	return nil
+}
+
+// tesseractLineKey 文本行在TSV中的位置：页、块、段落、行
+type tesseractLineKey struct {
+	page, block, par, line int
+}
+
+// parseTesseractTSV 解析 tesseract 的TSV输出，将同一行的词合并为一个识别结果
+// 行的外接矩形为各词矩形的并集，置信度为各词置信度的平均值并换算到0~1，逐字置信度取所在词的置信度
+func parseTesseractTSV(r io.Reader) ([]OCRResult, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("解析tesseract输出失败: %v", err)
+		}
+		return []OCRResult{}, nil
+	}
+
+	// 按表头定位各列，识别出的文字可能包含引号，因此不按CSV规则解析
+	header := strings.Split(strings.TrimRight(scanner.Text(), "\r"), "\t")
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"level", "page_num", "block_num", "par_num", "line_num", "left", "top", "width", "height", "conf", "text"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("tesseract输出缺少列: %s", name)
+		}
+	}
+
+	results := []OCRResult{}
+	confSums := []float64{}
+	confCounts := []int{}
+	lineIndex := map[tesseractLineKey]int{}
+
+	for scanner.Scan() {
+		record := strings.Split(strings.TrimRight(scanner.Text(), "\r"), "\t")
+		if len(record) < len(header) {
+			continue
+		}
+
+		field := func(name string) int {
+			v, _ := strconv.Atoi(strings.TrimSpace(record[columns[name]]))
+			return v
+		}
+
+		// 只处理第5级（词）
+		text := strings.TrimSpace(record[columns["text"]])
+		if field("level") != 5 || text == "" {
+			continue
+		}
+
+		key := tesseractLineKey{field("page_num"), field("block_num"), field("par_num"), field("line_num")}
+		left, top := field("left"), field("top")
+		right, bottom := left+field("width"), top+field("height")
+
+		i, ok := lineIndex[key]
+		if !ok {
+			i = len(results)
+			lineIndex[key] = i
+			var line OCRResult
+			line.BBox.XMin, line.BBox.YMin, line.BBox.XMax, line.BBox.YMax = left, top, right, bottom
+			results = append(results, line)
+			confSums = append(confSums, 0)
+			confCounts = append(confCounts, 0)
+		}
+
+		// 置信度为 -1 表示没有识别结果，此时各字符按完全可信处理
+		wordConf := 1.0
+		if conf, err := strconv.ParseFloat(strings.TrimSpace(record[columns["conf"]]), 64); err == nil && conf >= 0 {
+			wordConf = conf / 100
+			confSums[i] += conf
+			confCounts[i]++
+		}
+
+		// 词的每个字符使用该词的置信度，词间分隔符视为完全可信
+		line := &results[i]
+		separator := wordSeparator(line.Text, text)
+		for range separator {
+			line.CharConfidences = append(line.CharConfidences, 1)
+		}
+		for range text {
+			line.CharConfidences = append(line.CharConfidences, wordConf)
+		}
+		line.Text += separator + text
+		line.BBox.XMin, line.BBox.YMin = min(line.BBox.XMin, left), min(line.BBox.YMin, top)
+		line.BBox.XMax, line.BBox.YMax = max(line.BBox.XMax, right), max(line.BBox.YMax, bottom)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析tesseract输出失败: %v", err)
+	}
+
+	for i := range results {
+		if confCounts[i] > 0 {
+			results[i].Confidence = confSums[i] / float64(confCounts[i]) / 100
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 搜索模式
+const (
+	SearchModeText    = "text"    // 按整段文本搜索（默认）
+	SearchModeOptions = "options" // 拆分题干和选项，按题干与选项组合搜索
+)
+
+// 题干和选项组合打分时的权重
+const (
+	stemWeight      = 0.5
+	optionSetWeight = 0.5
+)
+
+// optionLabelPattern 匹配选项标签，如 A. B、 (C) D：，标签前须为开头、空白或标点
+var optionLabelPattern = regexp.MustCompile(`(?:^|[\s,，;；。])([（(]?([A-H])\s*(?:[.．、:：)）]|\s))`)
+
+// leadingLabelPattern 匹配选项文本开头的标签
+var leadingLabelPattern = regexp.MustCompile(`^\s*[（(]?[A-Ha-h]\s*[.．、:：)）]\s*`)
+
+// splitQuestionBlock 将一段题目文本拆分为题干和选项
+// 选项标签须从 A 开始依次出现，找不到时整段作为题干
+func splitQuestionBlock(text string) (string, []string) {
+	// 从 A 开始选出依次递增的标签，记录标签的起止位置
+	starts, ends := []int{}, []int{}
+	for _, m := range optionLabelPattern.FindAllStringSubmatchIndex(text, -1) {
+		if text[m[4]] == byte('A'+len(starts)) {
+			starts = append(starts, m[2])
+			ends = append(ends, m[3])
+		}
+	}
+	if len(starts) < 2 {
+		return strings.TrimSpace(text), nil
+	}
+
+	stem := strings.TrimSpace(text[:starts[0]])
+	options := []string{}
+	for i := range starts {
+		end := len(text)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		options = append(options, strings.TrimSpace(text[ends[i]:end]))
+	}
+	return stem, options
+}
+
+// stripOptionLabel 去掉选项开头的 A. 等标签
+func stripOptionLabel(option string) string {
+	return leadingLabelPattern.ReplaceAllString(option, "")
+}
+
+// optionSetScore 计算识别出的选项与题库选项的整体一致程度
+// 每个识别出的选项与题库中最相近且未被占用的选项配对，取平均分后按选项数量差异折算
+// 返回分数以及题库选项序号到配对的识别选项的映射
+func (e *ExamService) optionSetScore(captured []string, options []string) (float64, map[int]string) {
+	pairs := map[int]string{}
+	if len(captured) == 0 || len(options) == 0 {
+		return 0, pairs
+	}
+
+	normalized := make([]string, len(options))
+	for i, option := range options {
+		normalized[i] = strings.ToLower(e.normalizeText(stripOptionLabel(option)))
+	}
+
+	used := make([]bool, len(options))
+	total := 0.0
+	for _, c := range captured {
+		normalizedCaptured := strings.ToLower(e.normalizeText(c))
+		best, bestIdx := 0.0, -1
+		for i, option := range normalized {
+			if used[i] {
+				continue
+			}
+			score, _ := e.calculateOverlapScore(normalizedCaptured, option)
+			if score > best {
+				best, bestIdx = score, i
+			}
+		}
+		if bestIdx >= 0 {
+			used[bestIdx] = true
+			pairs[bestIdx] = c
+		}
+		total += best
+	}
+
+	coverage := float64(min(len(captured), len(options))) / float64(max(len(captured), len(options)))
+	return total / float64(len(captured)) * coverage, pairs
+}
+
+// SearchByOptions 将识别文本拆分为题干和选项，按题干与选项组合在所有启用的题库中搜索
+// 题干相近但选项不同的题目可以据此区分；识别不出选项时退回普通搜索
+func (e *ExamService) SearchByOptions(query string, filters SearchFilters) ([]SearchResult, error) {
+	stem, captured := splitQuestionBlock(query)
+	if len(captured) == 0 {
+		return e.SearchBanks(query, filters)
+	}
+	return e.searchStemAndOptions(stem, captured, query, filters), nil
+}
+
+// searchStemAndOptions 按题干与识别出的选项组合打分，query 用于从索引中筛选候选题目
+func (e *ExamService) searchStemAndOptions(stem string, captured []string, query string, filters SearchFilters) []SearchResult {
+	normalizedStem := strings.ToLower(e.normalizeText(stem))
+	results := []SearchResult{}
+
+	for _, bank := range answerStore.EnabledBanks() {
+		for _, answer := range bank.shortlist(query, filters) {
+			if !filters.matchItem(answer) {
+				continue
+			}
+
+			optionScore, pairs := e.optionSetScore(captured, answer.Options)
+			score := optionScore
+			if normalizedStem != "" {
+				stemScore, _ := e.calculateOverlapScore(normalizedStem, strings.ToLower(e.normalizeText(answer.Question)))
+				score = stemScore*stemWeight + optionScore*optionSetWeight
+			}
+			if score > 1.0 {
+				score = 1.0
+			}
+			if !filters.AccuracyFilters.accept(score) {
+				continue
+			}
+
+			// 选项只按与之配对的识别选项高亮
+			optionMatches := make(map[string][]int)
+			for i, option := range answer.Options {
+				optionMatches[option] = []int{}
+				if c, ok := pairs[i]; ok {
+					optionMatches[option] = e.calculateMatchesForOriginalText(option, c)
+				}
+			}
+
+			results = append(results, SearchResult{
+				Item:               answer,
+				Score:              score,
+				Matched:            "题干和选项匹配: " + stem,
+				QuestionMatches:    e.calculateMatchesForOriginalText(answer.Question, stem),
+				OptionMatches:      optionMatches,
+				AnswerMatches:      []int{},
+				ExplanationMatches: []int{},
+				BankID:             bank.ID,
+				BankName:           bank.Name,
+			})
+		}
+	}
+
+	// 按匹配度排序，同分时保持题库顺序
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	return results
+}
+
+// uniqueSortedInts 排序并去除重复的整数
+func uniqueSortedInts(values []int) []int {
+	sort.Ints(values)
+	unique := []int{}
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
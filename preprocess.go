@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+)
+
+// 反色方式
+const (
+	InvertNone   = ""       // 不反色
+	InvertAuto   = "auto"   // 深色背景时反色
+	InvertAlways = "always" // 总是反色
+)
+
+// 内置预处理方案名称
+const (
+	PreprocessNone        = "none"         // 不做处理
+	PreprocessStandard    = "standard"     // 灰度、对比度拉伸、深色背景反色、放大小图
+	PreprocessLowContrast = "low_contrast" // 在 standard 基础上自适应二值化，适合浅色文字的网页
+	PreprocessDark        = "dark"         // 深色主题：反色、对比度拉伸和二值化
+	PreprocessScan        = "scan"         // 拍照或扫描的试卷：在 low_contrast 基础上纠正倾斜
+)
+
+// 预处理参数
+const (
+	maxUpscale        = 4    // 放大小图时的最大倍数
+	darkBackgroundLum = 128  // 平均亮度低于此值视为深色背景
+	contrastClip      = 0.01 // 对比度拉伸时两端各忽略的像素比例
+	binarizeOffset    = 10   // 自适应二值化时比邻域均值暗多少才视为文字
+	maxSkewDegrees    = 5.0  // 纠正倾斜时尝试的最大角度
+	skewStepDegrees   = 0.5  // 纠正倾斜时尝试的角度步长
+)
+
+// PreprocessProfile OCR前的图片预处理方案
+// 启用任一处理时图片都会先转为灰度
+type PreprocessProfile struct {
+	Grayscale bool   `json:"grayscale"` // 转为灰度
+	Contrast  bool   `json:"contrast"`  // 对比度拉伸
+	Binarize  bool   `json:"binarize"`  // 自适应二值化
+	Invert    string `json:"invert"`    // 反色方式："auto"、"always" 或空
+	MinHeight int    `json:"minHeight"` // 图片高度小于此值时按整数倍放大，0 表示不放大
+	Deskew    bool   `json:"deskew"`    // 纠正倾斜
+}
+
+// builtinPreprocessProfiles 内置预处理方案
+var builtinPreprocessProfiles = map[string]PreprocessProfile{
+	PreprocessNone:        {},
+	PreprocessStandard:    {Grayscale: true, Contrast: true, Invert: InvertAuto, MinHeight: 100},
+	PreprocessLowContrast: {Grayscale: true, Contrast: true, Binarize: true, Invert: InvertAuto, MinHeight: 100},
+	PreprocessDark:        {Grayscale: true, Contrast: true, Binarize: true, Invert: InvertAlways, MinHeight: 100},
+	PreprocessScan:        {Grayscale: true, Contrast: true, Binarize: true, Invert: InvertAuto, MinHeight: 100, Deskew: true},
+}
+
+// enabled 判断方案是否需要处理图片
+func (p PreprocessProfile) enabled() bool {
+	return p.Grayscale || p.Contrast || p.Binarize || p.Invert != InvertNone || p.MinHeight > 0 || p.Deskew
+}
+
+// preprocessProfile 返回配置选用的预处理方案，自定义方案优先于同名内置方案
+func preprocessProfile(config OCRConfig) (PreprocessProfile, error) {
+	if config.Preprocess == "" {
+		return PreprocessProfile{}, nil
+	}
+	if profile, ok := config.Profiles[config.Preprocess]; ok {
+		return profile, nil
+	}
+	if profile, ok := builtinPreprocessProfiles[config.Preprocess]; ok {
+		return profile, nil
+	}
+	return PreprocessProfile{}, fmt.Errorf("预处理方案不存在: %s", config.Preprocess)
+}
+
+// GetPreprocessProfiles 获取内置的预处理方案
+func (e *ExamService) GetPreprocessProfiles() map[string]PreprocessProfile {
+	return builtinPreprocessProfiles
+}
+
+// preprocessImage 按配置选用的方案处理图片，未启用预处理时返回原图
+func preprocessImage(img image.Image, config OCRConfig) (image.Image, error) {
+	profile, err := preprocessProfile(config)
+	if err != nil {
+		return nil, err
+	}
+	if !profile.enabled() {
+		return img, nil
+	}
+	return applyPreprocess(img, profile), nil
+}
+
+// applyPreprocess 依次执行灰度、放大、反色、对比度拉伸、纠正倾斜和二值化
+func applyPreprocess(img image.Image, profile PreprocessProfile) *image.Gray {
+	gray := toGray(img)
+
+	if profile.MinHeight > 0 && gray.Bounds().Dy() > 0 && gray.Bounds().Dy() < profile.MinHeight {
+		scale := min((profile.MinHeight+gray.Bounds().Dy()-1)/gray.Bounds().Dy(), maxUpscale)
+		gray = upscale(gray, scale)
+	}
+
+	switch profile.Invert {
+	case InvertAlways:
+		invert(gray)
+	case InvertAuto:
+		if meanLuminance(gray) < darkBackgroundLum {
+			invert(gray)
+		}
+	}
+
+	if profile.Contrast {
+		stretchContrast(gray)
+	}
+	if profile.Deskew {
+		gray = deskew(gray)
+	}
+	if profile.Binarize {
+		gray = binarize(gray)
+	}
+	return gray
+}
+
+// toGray 将图片转为原点在 (0,0) 的灰度图
+func toGray(img image.Image) *image.Gray {
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+	return gray
+}
+
+// upscale 按整数倍双线性放大灰度图
+func upscale(src *image.Gray, scale int) *image.Gray {
+	if scale <= 1 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewGray(image.Rect(0, 0, w*scale, h*scale))
+	for y := 0; y < h*scale; y++ {
+		sy := (float64(y)+0.5)/float64(scale) - 0.5
+		y0 := int(math.Floor(sy))
+		fy := sy - float64(y0)
+		y0, y1 := clampInt(y0, 0, h-1), clampInt(y0+1, 0, h-1)
+		for x := 0; x < w*scale; x++ {
+			sx := (float64(x)+0.5)/float64(scale) - 0.5
+			x0 := int(math.Floor(sx))
+			fx := sx - float64(x0)
+			x0, x1 := clampInt(x0, 0, w-1), clampInt(x0+1, 0, w-1)
+
+			top := float64(src.GrayAt(x0, y0).Y)*(1-fx) + float64(src.GrayAt(x1, y0).Y)*fx
+			bottom := float64(src.GrayAt(x0, y1).Y)*(1-fx) + float64(src.GrayAt(x1, y1).Y)*fx
+			dst.Pix[y*dst.Stride+x] = uint8(math.Round(top*(1-fy) + bottom*fy))
+		}
+	}
+	return dst
+}
+
+// clampInt 将整数限制在 [lo, hi] 范围内
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// meanLuminance 计算灰度图的平均亮度
+func meanLuminance(gray *image.Gray) float64 {
+	if len(gray.Pix) == 0 {
+		return 255
+	}
+	total := 0
+	for _, p := range gray.Pix {
+		total += int(p)
+	}
+	return float64(total) / float64(len(gray.Pix))
+}
+
+// invert 反色
+func invert(gray *image.Gray) {
+	for i, p := range gray.Pix {
+		gray.Pix[i] = 255 - p
+	}
+}
+
+// stretchContrast 将亮度范围线性拉伸到 0~255，两端各忽略 contrastClip 比例的像素以排除噪点
+func stretchContrast(gray *image.Gray) {
+	if len(gray.Pix) == 0 {
+		return
+	}
+	var hist [256]int
+	for _, p := range gray.Pix {
+		hist[p]++
+	}
+
+	clip := int(float64(len(gray.Pix)) * contrastClip)
+	lo, hi := 0, 255
+	for count := 0; lo < 255 && count+hist[lo] <= clip; lo++ {
+		count += hist[lo]
+	}
+	for count := 0; hi > 0 && count+hist[hi] <= clip; hi-- {
+		count += hist[hi]
+	}
+	if hi <= lo {
+		return
+	}
+
+	var table [256]uint8
+	for i := range table {
+		v := (i - lo) * 255 / (hi - lo)
+		table[i] = uint8(clampInt(v, 0, 255))
+	}
+	for i, p := range gray.Pix {
+		gray.Pix[i] = table[p]
+	}
+}
+
+// binarize 自适应二值化：比邻域均值暗 binarizeOffset 以上的像素为文字（黑），其余为背景（白）
+// 邻域均值用积分图计算，邻域边长约为图片短边的八分之一
+func binarize(gray *image.Gray) *image.Gray {
+	w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+	if w == 0 || h == 0 {
+		return gray
+	}
+
+	// integral[(y+1)*(w+1)+(x+1)] 为 (0,0) 到 (x,y) 的像素和
+	integral := make([]int, (w+1)*(h+1))
+	for y := 0; y < h; y++ {
+		rowSum := 0
+		for x := 0; x < w; x++ {
+			rowSum += int(gray.Pix[y*gray.Stride+x])
+			integral[(y+1)*(w+1)+x+1] = integral[y*(w+1)+x+1] + rowSum
+		}
+	}
+
+	radius := max(min(w, h)/16, 7)
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := max(y-radius, 0), min(y+radius+1, h)
+		for x := 0; x < w; x++ {
+			x0, x1 := max(x-radius, 0), min(x+radius+1, w)
+			sum := integral[y1*(w+1)+x1] - integral[y0*(w+1)+x1] - integral[y1*(w+1)+x0] + integral[y0*(w+1)+x0]
+			mean := sum / ((y1 - y0) * (x1 - x0))
+
+			if int(gray.Pix[y*gray.Stride+x]) < mean-binarizeOffset {
+				dst.Pix[y*dst.Stride+x] = 0
+			} else {
+				dst.Pix[y*dst.Stride+x] = 255
+			}
+		}
+	}
+	return dst
+}
+
+// deskew 用水平投影法估计倾斜角度并旋转纠正
+// 文字行水平时各行深色像素数的差异最大，取使行投影平方和最大的角度
+func deskew(gray *image.Gray) *image.Gray {
+	w, h := gray.Bounds().Dx(), gray.Bounds().Dy()
+
+	// 收集深色像素，数量过多时等间隔采样
+	type point struct{ x, y float64 }
+	dark := []point{}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if gray.Pix[y*gray.Stride+x] < darkBackgroundLum {
+				dark = append(dark, point{float64(x), float64(y)})
+			}
+		}
+	}
+	if len(dark) < 2 {
+		return gray
+	}
+	if step := len(dark) / 20000; step > 1 {
+		sampled := make([]point, 0, len(dark)/step+1)
+		for i := 0; i < len(dark); i += step {
+			sampled = append(sampled, dark[i])
+		}
+		dark = sampled
+	}
+
+	bestAngle, bestScore := 0.0, -1.0
+	rows := map[int]int{}
+	for deg := -maxSkewDegrees; deg <= maxSkewDegrees+1e-9; deg += skewStepDegrees {
+		sin, cos := math.Sincos(deg * math.Pi / 180)
+		clear(rows)
+		for _, p := range dark {
+			rows[int(math.Round(p.y*cos-p.x*sin))]++
+		}
+		score := 0.0
+		for _, n := range rows {
+			score += float64(n) * float64(n)
+		}
+		if score > bestScore || (score == bestScore && math.Abs(deg) < math.Abs(bestAngle)) {
+			bestAngle, bestScore = deg, score
+		}
+	}
+	if bestAngle == 0 {
+		return gray
+	}
+	return rotate(gray, bestAngle)
+}
+
+// rotate 绕图片中心旋转灰度图，使倾斜 degrees 度的文字行变为水平，空出的区域填充白色
+func rotate(src *image.Gray, degrees float64) *image.Gray {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	cx, cy := float64(w)/2, float64(h)/2
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// 目标像素对应到原图的位置
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx := int(math.Round(dx*cos - dy*sin + cx))
+			sy := int(math.Round(dx*sin + dy*cos + cy))
+			if sx >= 0 && sx < w && sy >= 0 && sy < h {
+				dst.Pix[y*dst.Stride+x] = src.Pix[sy*src.Stride+sx]
+			} else {
+				dst.Pix[y*dst.Stride+x] = 255
+			}
+		}
+	}
+	return dst
+}
+
+// pngDataURL 将PNG图片数据编码为 data URL
+func pngDataURL(imageData []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(imageData)
+}
+
+// PreviewPreprocess 裁剪截图并按配置预处理，返回处理后的图片，用于调试预处理方案
+func (e *ExamService) PreviewPreprocess(area ScreenshotArea, config OCRConfig) (string, error) {
+	img, err := area.source()
+	if err != nil {
+		return "", err
+	}
+	img, err = preprocessImage(cropImage(img, area), config)
+	if err != nil {
+		return "", err
+	}
+	return imageDataURL(img)
+}
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"math/bits"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// watchEventName 监视结果的 Wails 事件名称
+const watchEventName = "watch:event"
+
+// 监视事件类型
+const (
+	WatchEventResult  = "result"  // 区域内容变化后的识别和搜索结果
+	WatchEventError   = "error"   // 截图或识别失败
+	WatchEventStopped = "stopped" // 监视已停止
+)
+
+// 监视默认参数
+const (
+	defaultWatchInterval      = time.Second
+	minWatchInterval          = 200 * time.Millisecond
+	defaultWatchHashDistance  = 6     // 差异哈希的汉明距离超过此值视为内容变化
+	defaultWatchPixelRatio    = 0.001 // 缩略图中变化像素的比例超过此值视为内容变化
+	watchPixelDelta           = 32    // 缩略图中灰度差超过此值的像素视为变化
+	watchThumbnailWidth       = 320   // 缩略图最大宽度
+	watchStreamKeepAlive      = 15 * time.Second
+	watchSubscriberBufferSize = 8
+)
+
+// WatchRequest 监视截图区域的请求
+type WatchRequest struct {
+	Region       string         `json:"region,omitempty"`       // 已保存的截图区域名称
+	Area         *CaptureRegion `json:"area,omitempty"`         // 未指定区域名称时使用的区域
+	Config       OCRConfig      `json:"config"`                 // OCR配置
+	Filters      SearchFilters  `json:"filters"`                // 搜索筛选条件
+	IntervalMs   int            `json:"intervalMs,omitempty"`   // 截图间隔，默认1000毫秒，最小200毫秒
+	HashDistance int            `json:"hashDistance,omitempty"` // 差异哈希的汉明距离阈值，默认6
+	PixelRatio   float64        `json:"pixelRatio,omitempty"`   // 变化像素比例阈值，默认0.001
+}
+
+// WatchEvent 监视事件，通过 Wails 事件和HTTP流推送
+type WatchEvent struct {
+	Type    string               `json:"type"`              // 事件类型：result、error 或 stopped
+	Session int                  `json:"session"`           // 监视序号，每次开始监视时递增
+	Time    time.Time            `json:"time"`              // 事件时间
+	Frame   int                  `json:"frame"`             // 截图序号
+	Result  *CaptureSearchResult `json:"result,omitempty"`  // 识别和搜索结果
+	Message string               `json:"message,omitempty"` // 错误信息
+}
+
+// WatchStatus 监视状态
+type WatchStatus struct {
+	Running    bool          `json:"running"`    // 是否正在监视
+	Session    int           `json:"session"`    // 监视序号，每次开始监视时递增
+	Region     CaptureRegion `json:"region"`     // 监视的截图区域
+	IntervalMs int           `json:"intervalMs"` // 截图间隔
+	Frames     int           `json:"frames"`     // 已截图次数
+	Changes    int           `json:"changes"`    // 检测到内容变化并识别的次数
+	LastError  string        `json:"lastError,omitempty"`
+}
+
+// frameSignature 截图区域的特征，用于判断内容是否变化
+type frameSignature struct {
+	hash      uint64      // 9x8 差异哈希
+	thumbnail *image.Gray // 灰度缩略图
+}
+
+// RegionWatcher 截图区域监视器，同一时间只监视一个区域
+type RegionWatcher struct {
+	control     sync.Mutex // 串行执行 Start 和 Stop，避免并发开始监视时之前的监视协程无法停止
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	done        chan struct{}
+	status      WatchStatus
+	subscribers map[chan WatchEvent]struct{}
+}
+
+// 全局截图区域监视器
+var regionWatcher = &RegionWatcher{subscribers: map[chan WatchEvent]struct{}{}}
+
+// Start 开始监视截图区域，已在监视时先停止之前的监视
+func (w *RegionWatcher) Start(e *ExamService, request WatchRequest) error {
+	region, err := resolveCaptureRegion(request.Region, request.Area)
+	if err != nil {
+		return err
+	}
+	if _, err := ocrEngineFor(request.Config); err != nil {
+		return err
+	}
+
+	interval := time.Duration(request.IntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	if interval < minWatchInterval {
+		interval = minWatchInterval
+	}
+	if request.HashDistance <= 0 {
+		request.HashDistance = defaultWatchHashDistance
+	}
+	if request.PixelRatio <= 0 {
+		request.PixelRatio = defaultWatchPixelRatio
+	}
+
+	w.control.Lock()
+	defer w.control.Unlock()
+	w.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	w.mu.Lock()
+	w.cancel, w.done = cancel, done
+	session := w.status.Session + 1
+	w.status = WatchStatus{Running: true, Session: session, Region: region, IntervalMs: int(interval / time.Millisecond)}
+	w.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		w.run(ctx, e, session, region, interval, request)
+	}()
+	return nil
+}
+
+// Stop 停止监视并等待监视协程退出
+func (w *RegionWatcher) Stop() {
+	w.control.Lock()
+	defer w.control.Unlock()
+	w.stop()
+}
+
+// stop 停止监视并等待监视协程退出，调用方需持有 control 锁
+func (w *RegionWatcher) stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Status 返回监视状态
+func (w *RegionWatcher) Status() WatchStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// Subscribe 订阅监视事件，返回事件通道和取消订阅函数
+func (w *RegionWatcher) Subscribe() (<-chan WatchEvent, func()) {
+	events := make(chan WatchEvent, watchSubscriberBufferSize)
+
+	w.mu.Lock()
+	w.subscribers[events] = struct{}{}
+	w.mu.Unlock()
+
+	return events, func() {
+		w.mu.Lock()
+		delete(w.subscribers, events)
+		w.mu.Unlock()
+	}
+}
+
+// publish 推送监视事件，订阅者处理不及时时丢弃事件而不阻塞监视
+func (w *RegionWatcher) publish(event WatchEvent) {
+	w.mu.Lock()
+	for events := range w.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+	w.mu.Unlock()
+
+	if app := application.Get(); app != nil {
+		app.Event.Emit(watchEventName, event)
+	}
+}
+
+// run 定时截图，区域内容变化并稳定后识别和搜索
+// 内容变化后要等下一次截图与本次一致才处理，避免在页面切换动画中途识别
+func (w *RegionWatcher) run(ctx context.Context, e *ExamService, session int, region CaptureRegion, interval time.Duration, request WatchRequest) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous, handled *frameSignature
+	lastError := ""
+	frame := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.mu.Lock()
+			w.status.Running = false
+			w.mu.Unlock()
+			w.publish(WatchEvent{Session: session, Type: WatchEventStopped, Time: time.Now(), Frame: frame})
+			return
+		case <-ticker.C:
+		}
+
+		frame++
+		start := time.Now()
+		screenshot, err := e.captureDisplay(region.DisplayID, region.DisplayName)
+		if err != nil {
+			// 相同的错误只推送一次
+			if err.Error() != lastError {
+				lastError = err.Error()
+				w.recordError(lastError)
+				w.publish(WatchEvent{Session: session, Type: WatchEventError, Time: time.Now(), Frame: frame, Message: lastError})
+			}
+			continue
+		}
+		screenshotTime := time.Since(start)
+
+		current := signatureOf(cropImage(screenshot, region.area(screenshot)))
+		changed := handled == nil || current.differs(*handled, request.HashDistance, request.PixelRatio)
+		stable := previous != nil && !current.differs(*previous, request.HashDistance, request.PixelRatio)
+		previous = &current
+
+		w.mu.Lock()
+		w.status.Frames = frame
+		w.mu.Unlock()
+
+		if !changed || !stable {
+			continue
+		}
+		handled = &current
+
+		result := CaptureSearchResult{Region: region}
+		result.Timings.Screenshot = milliseconds(screenshotTime)
+		if err := e.searchScreenshot(&result, screenshot, request.Config, request.Filters); err != nil {
+			lastError = err.Error()
+			w.recordError(lastError)
+			w.publish(WatchEvent{Session: session, Type: WatchEventError, Time: time.Now(), Frame: frame, Message: lastError})
+			continue
+		}
+		result.Timings.Total = milliseconds(time.Since(start))
+		lastError = ""
+
+		w.mu.Lock()
+		w.status.Changes++
+		w.status.LastError = ""
+		w.mu.Unlock()
+		w.publish(WatchEvent{Session: session, Type: WatchEventResult, Time: time.Now(), Frame: frame, Result: &result})
+	}
+}
+
+// recordError 记录最近一次错误
+func (w *RegionWatcher) recordError(message string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.LastError = message
+}
+
+// signatureOf 计算截图区域的差异哈希和灰度缩略图
+func signatureOf(img image.Image) frameSignature {
+	bounds := img.Bounds()
+	width := min(bounds.Dx(), watchThumbnailWidth)
+	height := max(bounds.Dy()*width/max(bounds.Dx(), 1), 1)
+
+	// 差异哈希：每行9个像素，相邻像素左侧更亮时对应位为1
+	small := grayThumbnail(img, 9, 8)
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if small.GrayAt(x, y).Y > small.GrayAt(x+1, y).Y {
+				hash |= 1
+			}
+		}
+	}
+
+	return frameSignature{hash: hash, thumbnail: grayThumbnail(img, width, height)}
+}
+
+// differs 判断两帧内容是否不同：差异哈希距离超过阈值，或缩略图中变化的像素比例超过阈值
+// 差异哈希能发现整体布局变化，像素比较能发现题号等小范围文字变化
+func (s frameSignature) differs(other frameSignature, hashDistance int, pixelRatio float64) bool {
+	if bits.OnesCount64(s.hash^other.hash) > hashDistance {
+		return true
+	}
+	if s.thumbnail.Bounds() != other.thumbnail.Bounds() {
+		return true
+	}
+
+	changed := 0
+	for i, v := range s.thumbnail.Pix {
+		if diff := int(v) - int(other.thumbnail.Pix[i]); diff > watchPixelDelta || diff < -watchPixelDelta {
+			changed++
+		}
+	}
+	return float64(changed) > pixelRatio*float64(len(s.thumbnail.Pix))
+}
+
+// grayThumbnail 按区域平均将图片缩小为指定尺寸的灰度图
+func grayThumbnail(img image.Image, width, height int) *image.Gray {
+	bounds := img.Bounds()
+	thumbnail := image.NewGray(image.Rect(0, 0, width, height))
+	if bounds.Empty() {
+		return thumbnail
+	}
+
+	for ty := 0; ty < height; ty++ {
+		y0 := bounds.Min.Y + ty*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(ty+1)*bounds.Dy()/height, y0+1)
+		for tx := 0; tx < width; tx++ {
+			x0 := bounds.Min.X + tx*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(tx+1)*bounds.Dx()/width, x0+1)
+
+			sum, count := 0, 0
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += int(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+					count++
+				}
+			}
+			thumbnail.Pix[ty*thumbnail.Stride+tx] = uint8(sum / count)
+		}
+	}
+	return thumbnail
+}
+
+// StartWatch 开始监视截图区域，区域内容变化后自动识别和搜索，结果通过 watch:event 事件推送
+func (e *ExamService) StartWatch(request WatchRequest) error {
+	return regionWatcher.Start(e, request)
+}
+
+// StopWatch 停止监视截图区域
+func (e *ExamService) StopWatch() {
+	regionWatcher.Stop()
+}
+
+// GetWatchStatus 获取监视状态
+func (e *ExamService) GetWatchStatus() WatchStatus {
+	return regionWatcher.Status()
+}
+
+// WatchResponse HTTP监视响应结构
+type WatchResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Status  WatchStatus `json:"status"`
+}
+
+// handleWatch 处理HTTP监视请求
+// GET 获取监视状态，POST 开始监视，DELETE 停止监视
+func handleWatch(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	var err error
+	switch r.Method {
+	case "GET":
+	case "POST":
+		var req WatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = examService.StartWatch(req)
+	case "DELETE":
+		examService.StopWatch()
+	default:
+		http.Error(w, "只支持GET、POST和DELETE方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := WatchResponse{Success: err == nil, Status: examService.GetWatchStatus()}
+	if err != nil {
+		response.Message = "开始监视失败: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleWatchStream 以 Server-Sent Events 格式推送监视事件，连接断开时取消订阅
+func handleWatchStream(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许GET方法
+	if r.Method != "GET" {
+		http.Error(w, "只支持GET方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "不支持流式响应", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := regionWatcher.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(watchStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			// 注释行用于保持连接
+			fmt.Fprint(w, ": ping\n\n")
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
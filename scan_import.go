@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 扫描件导入的默认参数
+const (
+	defaultScanDPI  = 200
+	pdftoppmTimeout = 5 * time.Minute
+	maxNumberGap    = 3 // 题号最多跳过的数量，容忍个别题号未识别出来
+)
+
+// scanImageExtensions 扫描件目录中会被识别的图片格式
+var scanImageExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".webp": true}
+
+// sectionHeadingPattern 匹配大题标题，如 一、单项选择题（每题2分）
+var sectionHeadingPattern = regexp.MustCompile(`^\s*[一二三四五六七八九十]+\s*[、.．]\s*(.*)$`)
+
+// answerKeyHeadingPattern 匹配答案页的标题，如 参考答案 【答案及解析】
+var answerKeyHeadingPattern = regexp.MustCompile(`^\s*[\[【]?\s*(?:参考答案|标准答案|答案)(?:及解析|与解析)?\s*[\]】]?\s*[:：]?\s*`)
+
+// answerLinePattern 匹配题目后的答案行，如 答案：B 【正确答案】AC
+var answerLinePattern = regexp.MustCompile(`^\s*[\[【]?\s*(?:正确答案|参考答案|标准答案|答案)\s*[\]】]?\s*[:：]?\s*`)
+
+// explanationLinePattern 匹配题目后的解析行，如 解析：…… 【答案解析】
+var explanationLinePattern = regexp.MustCompile(`^\s*[\[【]?\s*(?:答案解析|试题解析|解析)\s*[\]】]?\s*[:：]?\s*`)
+
+// answerKeyEntryPattern 匹配答案页中的答案，如 1.A 2、BC 3-5 ABD 6 √
+var answerKeyEntryPattern = regexp.MustCompile(`(\d{1,4})\s*[-~～—]+\s*(\d{1,4})\s*[.．、:：]?\s*([A-H]+)|(\d{1,4})\s*[.．、:：)）]?\s*([A-H]+|√|✓|×|✗|正确|错误|对|错)`)
+
+// filledAnswerPattern 匹配题干括号中已填写的答案，如 下列说法正确的是（ B ）
+var filledAnswerPattern = regexp.MustCompile(`[（(]\s*([A-H]{1,8}|√|✓|×|✗)\s*[)）]`)
+
+// answerLettersPattern 匹配答案开头的字母，允许以空白或顿号等分隔，如 A、C
+var answerLettersPattern = regexp.MustCompile(`^[A-H](?:[\s,，、;；]*[A-H])*`)
+
+// 判断题答案
+const (
+	judgeTrue  = "正确"
+	judgeFalse = "错误"
+)
+
+// ScanImportRequest 从扫描件导入题目的请求
+// 不接受 pdftoppm 路径，渲染PDF时只从 PATH 中查找，以免HTTP接口的调用方借此执行任意程序
+type ScanImportRequest struct {
+	Path   string    `json:"path"`          // 图片目录、单张图片或PDF文件
+	Config OCRConfig `json:"config"`        // OCR配置
+	DPI    int       `json:"dpi,omitempty"` // PDF渲染分辨率，默认200
+}
+
+// ScanPage 一页扫描件的识别情况
+type ScanPage struct {
+	Source     string  `json:"source"`               // 图片文件名或 PDF 页码
+	Lines      int     `json:"lines"`                // 识别出的文本行数
+	Confidence float64 `json:"confidence,omitempty"` // 平均置信度
+	Error      string  `json:"error,omitempty"`      // 识别失败的原因
+}
+
+// ScanImportResult 扫描件导入结果，题目为待审核的草稿，确认后通过 ImportBank 保存为题库
+// 诊断报告中的行号为题目在草稿中的序号
+type ScanImportResult struct {
+	Answers []AnswerItem  `json:"answers"`
+	Report  *ImportReport `json:"report"`
+	Pages   []ScanPage    `json:"pages"`
+}
+
+// scanLine 带来源页的一行识别文字
+type scanLine struct {
+	text string
+	page string
+}
+
+// scanBlock 切分出的一道题
+type scanBlock struct {
+	number      int
+	page        string
+	section     string   // 所在大题的题型
+	lines       []string // 题号、题干和选项
+	answer      []string // 题目后的答案
+	explanation []string // 题目后的解析
+}
+
+// answerKey 答案页中的答案，同一题号按出现顺序对应各大题中的同号题目
+type answerKey map[int][][]string
+
+// ImportScans 识别扫描件中的题目，生成待审核的题目草稿
+// path 可以是图片目录（按文件名中的数字顺序排列）、单张图片或PDF文件（通过 pdftoppm 渲染为图片）
+func (e *ExamService) ImportScans(request ScanImportRequest) (ScanImportResult, error) {
+	if _, err := ocrEngineFor(request.Config); err != nil {
+		return ScanImportResult{}, err
+	}
+
+	files, labels, cleanup, err := scanPageFiles(request)
+	if err != nil {
+		return ScanImportResult{}, err
+	}
+	defer cleanup()
+
+	result := ScanImportResult{Pages: []ScanPage{}}
+	report := &ImportReport{Issues: []ImportIssue{}, seen: map[string]int{}}
+
+	var lines []scanLine
+	for i, file := range files {
+		page := ScanPage{Source: labels[i]}
+		pageLines, confidence, err := e.recognizeScanPage(file, request.Config)
+		if err != nil {
+			page.Error = err.Error()
+			report.addIssue(0, IssueOCRError, "%s 识别失败: %v", page.Source, err)
+		}
+		page.Lines, page.Confidence = len(pageLines), confidence
+		result.Pages = append(result.Pages, page)
+		for _, line := range pageLines {
+			lines = append(lines, scanLine{text: line, page: page.Source})
+		}
+	}
+
+	blocks, key := segmentScanLines(lines)
+	result.Answers = buildScanAnswers(blocks, key, report)
+	result.Report = report
+	return result, nil
+}
+
+// recognizeScanPage 识别一页扫描件，返回按阅读顺序排列的文本行
+func (e *ExamService) recognizeScanPage(file string, config OCRConfig) ([]string, float64, error) {
+	img, err := readScreenshotFile(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	run, err := e.runOCR(ScreenshotArea{screenshot: img}, config)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orderOCRLines(run.results), run.confidence, nil
+}
+
+// scanPageFiles 列出扫描件的各页图片，返回图片路径、页面名称和清理临时文件的函数
+func scanPageFiles(request ScanImportRequest) ([]string, []string, func(), error) {
+	noop := func() {}
+	path := strings.TrimSpace(request.Path)
+	if path == "" {
+		return nil, nil, noop, fmt.Errorf("未指定扫描件路径")
+	}
+	request.Path = path
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, noop, fmt.Errorf("读取扫描件失败: %v", err)
+	}
+
+	switch {
+	case info.IsDir():
+		files, err := listScanImages(path)
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		labels := make([]string, len(files))
+		for i, file := range files {
+			labels[i] = filepath.Base(file)
+		}
+		return files, labels, noop, nil
+	case strings.EqualFold(filepath.Ext(path), ".pdf"):
+		dir, err := os.MkdirTemp("", "exam-scan-")
+		if err != nil {
+			return nil, nil, noop, fmt.Errorf("创建临时目录失败: %v", err)
+		}
+		cleanup := func() { os.RemoveAll(dir) }
+		if err := renderPDF(request, dir); err != nil {
+			cleanup()
+			return nil, nil, noop, err
+		}
+		files, err := listScanImages(dir)
+		if err != nil {
+			cleanup()
+			return nil, nil, noop, err
+		}
+		labels := make([]string, len(files))
+		for i := range files {
+			labels[i] = fmt.Sprintf("%s 第%d页", filepath.Base(path), i+1)
+		}
+		return files, labels, cleanup, nil
+	case scanImageExtensions[strings.ToLower(filepath.Ext(path))]:
+		return []string{path}, []string{filepath.Base(path)}, noop, nil
+	default:
+		return nil, nil, noop, fmt.Errorf("不支持的扫描件格式: %s", filepath.Ext(path))
+	}
+}
+
+// renderPDF 调用 pdftoppm 将PDF的每一页渲染为PNG图片
+func renderPDF(request ScanImportRequest, dir string) error {
+	tool, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return fmt.Errorf("未找到 pdftoppm，请安装 poppler-utils，也可以先将PDF转换为图片目录")
+	}
+	dpi := request.DPI
+	if dpi <= 0 {
+		dpi = defaultScanDPI
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pdftoppmTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, "-r", strconv.Itoa(dpi), "-png", request.Path, filepath.Join(dir, "page"))
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("执行pdftoppm失败: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// listScanImages 列出目录中的图片，按文件名中的数字顺序排列，如 page2 排在 page10 之前
+func listScanImages(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && scanImageExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("目录中没有图片: %s", dir)
+	}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return naturalLess(names[i], names[j])
+	})
+	files := make([]string, len(names))
+	for i, name := range names {
+		files[i] = filepath.Join(dir, name)
+	}
+	return files, nil
+}
+
+// naturalLess 按自然顺序比较文件名，连续的数字按数值比较
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, _ := strconv.Atoi(da)
+			nb, _ := strconv.Atoi(db)
+			if na != nb {
+				return na < nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			return ra < rb
+		}
+		a, b = a[sa:], b[sb:]
+	}
+	return len(a) < len(b)
+}
+
+// leadingDigits 返回字符串开头的连续数字
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
+
+// segmentScanLines 将各页文本行切分为题目，并收集答案页中的答案
+// 题号须在上一题之后依次递增（允许跳过个别未识别的题号），大题标题之后题号可以重新从头开始；
+// 第一道题之前的试卷标题、考试说明等文字被忽略
+func segmentScanLines(lines []scanLine) ([]*scanBlock, answerKey) {
+	blocks := []*scanBlock{}
+	key := answerKey{}
+	var current *scanBlock
+	section := ""
+	inKey, inExplanation := false, false
+
+	// 题目之后单独一行的“答案：”“参考答案”等，既可能是本题答案写在下一行，也可能是答案页的标题，
+	// 由下一行决定：能解析为答案时作为本题答案，是以题号开头的答案时进入答案页
+	pending, pendingTitle := false, false
+
+	for _, line := range lines {
+		text := strings.TrimSpace(line.text)
+		if text == "" {
+			continue
+		}
+
+		if pending {
+			pending = false
+			if answer := parseAnswerValue(text); answer != nil && !startsAnswerKey(text) {
+				inExplanation = current.setAnswer(text)
+				continue
+			}
+			// 下一题的题干可能恰好以 对、错 开头，只有一个答案且题号紧接本题时仍作为下一题；
+			// 不带冒号的标题之后紧跟大题标题，如 参考答案 / 一、单项选择题，也是答案页
+			single := len(answerKeyEntryPattern.FindAllString(text, 2)) < 2
+			if (startsAnswerKey(text) && !(single && current.followedBy(text))) || (pendingTitle && sectionHeadingPattern.MatchString(text)) {
+				inKey, current = true, nil
+				key.add(text)
+				continue
+			}
+		}
+
+		// 大题标题：记录题型，之后的题号可以重新开始
+		if m := sectionHeadingPattern.FindStringSubmatch(text); m != nil && !inKey {
+			if sectionType := questionTypeOf(m[1]); sectionType != "" {
+				section, current, inExplanation = sectionType, nil, false
+				continue
+			}
+		}
+
+		// 答案页：第一道题之前的标题或带有以题号开头的答案的标题，之后的所有文字都按答案解析
+		if loc := answerKeyHeadingPattern.FindStringIndex(text); loc != nil {
+			switch {
+			case inKey || current == nil || isAnswerKeyLine(text[loc[1]:]):
+				inKey, current = true, nil
+				key.add(text[loc[1]:])
+				continue
+			case loc[1] == len(text):
+				pending, pendingTitle = true, !strings.ContainsAny(text, ":：")
+				continue
+			}
+		}
+		if inKey {
+			key.add(text)
+			continue
+		}
+
+		// 新题目
+		if number, ok := scanQuestionNumber(text); ok && (current == nil || (number > current.number && number <= current.number+maxNumberGap)) {
+			current = &scanBlock{number: number, page: line.page, section: section, lines: []string{text}}
+			blocks = append(blocks, current)
+			inExplanation = false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		// 题目后的答案和解析
+		if loc := answerLinePattern.FindStringIndex(text); loc != nil {
+			inExplanation = current.setAnswer(text[loc[1]:])
+			continue
+		}
+		if loc := explanationLinePattern.FindStringIndex(text); loc != nil {
+			current.explanation = append(current.explanation, text[loc[1]:])
+			inExplanation = true
+			continue
+		}
+		if inExplanation {
+			current.explanation = append(current.explanation, text)
+			continue
+		}
+		current.lines = append(current.lines, text)
+	}
+	return blocks, key
+}
+
+// setAnswer 记录答案行中的答案，答案后紧跟解析时一并记录，返回之后的行是否属于解析
+func (b *scanBlock) setAnswer(value string) bool {
+	explanation := false
+	if m := explanationLinePattern.FindStringIndex(value); m != nil {
+		// 答案与解析在同一行，如 答案：B 解析：……
+		b.explanation = append(b.explanation, value[m[1]:])
+		value, explanation = "", true
+	} else if i := strings.Index(value, "解析"); i > 0 {
+		b.explanation = append(b.explanation, explanationLinePattern.ReplaceAllString(value[i:], ""))
+		value, explanation = value[:i], true
+	}
+	b.answer = parseAnswerValue(value)
+	return explanation
+}
+
+// followedBy 判断一行是否以本题之后的题号开头，允许跳过个别未识别的题号
+func (b *scanBlock) followedBy(text string) bool {
+	number, ok := scanQuestionNumber(text)
+	return ok && number > b.number && number <= b.number+maxNumberGap
+}
+
+// startsAnswerKey 判断一行是否是答案页中的答案，允许以大题序号开头，如 1.A 2.B 或 一、1-5 ABCDA
+func startsAnswerKey(text string) bool {
+	if m := sectionHeadingPattern.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	return isAnswerKeyLine(text)
+}
+
+// scanQuestionNumber 识别行首的题号，题型提示可以在题号之前，如 【单选题】1.
+func scanQuestionNumber(text string) (int, bool) {
+	if loc := questionTypePattern.FindStringIndex(text); loc != nil {
+		text = text[loc[1]:]
+	}
+	m := questionNumberPattern.FindStringSubmatchIndex(text)
+	if m == nil || followedByDigit(text, m[1]) {
+		return 0, false
+	}
+	for g := 1; g <= 3; g++ {
+		if m[2*g] >= 0 {
+			number, err := strconv.Atoi(text[m[2*g]:m[2*g+1]])
+			return number, err == nil
+		}
+	}
+	return 0, false
+}
+
+// isAnswerKeyLine 判断文字是否是答案页中以题号开头的答案，如 1.A 2.B
+func isAnswerKeyLine(text string) bool {
+	loc := answerKeyEntryPattern.FindStringIndex(text)
+	return loc != nil && strings.TrimSpace(text[:loc[0]]) == ""
+}
+
+// add 解析一行答案页文字，如 1.A 2.BC 或 1-5 ABCDA
+func (k answerKey) add(text string) {
+	for _, m := range answerKeyEntryPattern.FindAllStringSubmatch(text, -1) {
+		if m[1] != "" {
+			// 题号范围，字母依次对应各题
+			start, _ := strconv.Atoi(m[1])
+			end, _ := strconv.Atoi(m[2])
+			if end-start+1 != len(m[3]) {
+				continue
+			}
+			for i, letter := range m[3] {
+				k[start+i] = append(k[start+i], []string{string(letter)})
+			}
+			continue
+		}
+		number, _ := strconv.Atoi(m[4])
+		if answer := parseAnswerValue(m[5]); len(answer) > 0 {
+			k[number] = append(k[number], answer)
+		}
+	}
+}
+
+// take 取出题号对应的下一个答案
+func (k answerKey) take(number int) []string {
+	answers := k[number]
+	if len(answers) == 0 {
+		return nil
+	}
+	k[number] = answers[1:]
+	return answers[0]
+}
+
+// parseAnswerValue 解析答案文字，选择题返回大写字母列表，判断题返回 正确 或 错误，无法识别时返回 nil
+func parseAnswerValue(value string) []string {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return nil
+	case strings.HasPrefix(value, "√"), strings.HasPrefix(value, "✓"), strings.HasPrefix(value, "对"), strings.HasPrefix(value, "正确"):
+		return []string{judgeTrue}
+	case strings.HasPrefix(value, "×"), strings.HasPrefix(value, "✗"), strings.HasPrefix(value, "错"):
+		return []string{judgeFalse}
+	}
+
+	letters := answerLettersPattern.FindString(value)
+	// 字母后紧跟其他英文字母时是单词而不是答案
+	if next, _ := utf8.DecodeRuneInString(value[len(letters):]); letters == "" || (next < utf8.RuneSelf && unicode.IsLetter(next)) {
+		return nil
+	}
+	answer := []string{}
+	for _, r := range letters {
+		if r >= 'A' && r <= 'H' {
+			answer = append(answer, string(r))
+		}
+	}
+	return answer
+}
+
+// buildScanAnswers 将切分出的题目转换为答案项，并在诊断报告中记录缺少答案、答案超出选项范围等问题
+// 缺少答案或答案有误的题目仍保留在草稿中，便于审核时补全；题目为空或重复的题目被跳过
+func buildScanAnswers(blocks []*scanBlock, key answerKey, report *ImportReport) []AnswerItem {
+	answers := []AnswerItem{}
+	for i, block := range blocks {
+		row := i + 1
+		report.TotalRows++
+
+		q := parseQuestionLines(block.lines)
+		answer := block.answer
+		if len(answer) == 0 {
+			answer = key.take(block.number)
+		}
+		stem := q.Stem
+		if m := filledAnswerPattern.FindStringSubmatchIndex(stem); m != nil {
+			// 题干括号中已填写答案时清空括号，答案以答案行或答案页为准
+			if len(answer) == 0 {
+				answer = parseAnswerValue(stem[m[2]:m[3]])
+			}
+			stem = stem[:m[0]] + "（ ）" + stem[m[1]:]
+		}
+
+		item := AnswerItem{
+			Type:        scanQuestionType(q, block.section, answer),
+			Question:    stem,
+			Options:     q.Options,
+			Answer:      answer,
+			Explanation: joinWrappedLines(strings.Join(block.explanation, "\n")),
+			Source:      fmt.Sprintf("%s 第%d题", block.page, block.number),
+		}
+		if item.Answer == nil {
+			item.Answer = []string{}
+		}
+
+		if item.Question == "" {
+			report.addIssue(row, IssueEmptyQuestion, "%s: 题目为空", item.Source)
+			report.SkippedRows++
+			continue
+		}
+		if first, exists := report.seen[item.Question]; exists {
+			report.addIssue(row, IssueDuplicate, "%s: 题目与第%d题重复", item.Source, first)
+			report.SkippedRows++
+			continue
+		}
+		report.seen[item.Question] = row
+
+		if len(item.Answer) == 0 {
+			report.addIssue(row, IssueMissingAnswer, "%s: 未找到答案", item.Source)
+		}
+		for _, ans := range item.Answer {
+			if idx, isLetter := maxAnswerLetterIndex(ans); isLetter && idx >= len(item.Options) {
+				report.addIssue(row, IssueInvalidAnswer, "%s: 答案%s超出选项范围（共%d个选项）", item.Source, ans, len(item.Options))
+			}
+		}
+
+		report.ImportedRows++
+		answers = append(answers, item)
+	}
+	return answers
+}
+
+// scanQuestionType 确定题型：依次使用题目中的题型提示、大题标题，最后根据答案和选项推断
+func scanQuestionType(q ParsedQuestion, section string, answer []string) string {
+	switch {
+	case q.Type != "":
+		return q.Type
+	case section != "":
+		return section
+	case len(answer) == 1 && (answer[0] == judgeTrue || answer[0] == judgeFalse):
+		return QuestionTypeJudge
+	case len(answer) > 1:
+		return QuestionTypeMultiple
+	case len(q.Options) > 0:
+		return QuestionTypeSingle
+	}
+	return ""
+}
+
+// ScanImportResponse HTTP扫描件导入响应结构
+type ScanImportResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Results []AnswerItem  `json:"results,omitempty"`
+	Report  *ImportReport `json:"report,omitempty"`
+	Pages   []ScanPage    `json:"pages,omitempty"`
+}
+
+// handleImportScans 处理HTTP扫描件导入请求
+func handleImportScans(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许POST方法
+	if r.Method != "POST" {
+		http.Error(w, "只支持POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 解析请求体
+	var req ScanImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	result, err := examService.ImportScans(req)
+	if err != nil {
+		response := ScanImportResponse{
+			Success: false,
+			Message: "扫描件识别失败: " + err.Error(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := ScanImportResponse{
+		Success: true,
+		Results: result.Answers,
+		Report:  result.Report,
+		Pages:   result.Pages,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// scanLinesOf 将文字按行转换为同一页的识别结果
+func scanLinesOf(texts ...string) []scanLine {
+	lines := make([]scanLine, len(texts))
+	for i, text := range texts {
+		lines[i] = scanLine{text: text, page: "1.png"}
+	}
+	return lines
+}
+
+func TestSegmentAnswerOnNextLine(t *testing.T) {
+	blocks, key := segmentScanLines(scanLinesOf(
+		"1. 下列属于安全色的是（ ）",
+		"A. 红色 B. 粉色",
+		"答案：",
+		"B",
+		"解析：红色表示禁止",
+		"2. 安全帽属于个人防护用品",
+		"【答案】",
+		"对",
+		"3. 以下说法正确的是（ ）",
+		"A. 甲 B. 乙 C. 丙",
+		"答案：AC",
+	))
+
+	if len(key) != 0 {
+		t.Fatalf("不应识别出答案页: %v", key)
+	}
+	want := []struct {
+		number int
+		answer []string
+	}{{1, []string{"B"}}, {2, []string{judgeTrue}}, {3, []string{"A", "C"}}}
+	if len(blocks) != len(want) {
+		t.Fatalf("切分出%d道题，应为%d道", len(blocks), len(want))
+	}
+	for i, w := range want {
+		if blocks[i].number != w.number || !reflect.DeepEqual(blocks[i].answer, w.answer) {
+			t.Errorf("第%d道题为 %d %v，应为 %d %v", i+1, blocks[i].number, blocks[i].answer, w.number, w.answer)
+		}
+	}
+	if !reflect.DeepEqual(blocks[0].explanation, []string{"红色表示禁止"}) {
+		t.Errorf("第1题解析为%v", blocks[0].explanation)
+	}
+}
+
+func TestSegmentAnswerKeyRange(t *testing.T) {
+	lines := []string{"一、单项选择题"}
+	for _, text := range []string{"1. 甲", "2. 乙", "3. 丙", "4. 丁", "5. 戊"} {
+		lines = append(lines, text, "A. 是 B. 否 C. 不确定 D. 以上都不是")
+	}
+	lines = append(lines, "参考答案", "一、单项选择题", "1-5 ABCDA")
+	blocks, key := segmentScanLines(scanLinesOf(lines...))
+
+	if len(blocks) != 5 {
+		t.Fatalf("切分出%d道题，应为5道", len(blocks))
+	}
+	if len(blocks[4].lines) != 2 {
+		t.Errorf("答案页被并入最后一题: %v", blocks[4].lines)
+	}
+	for i, letter := range []string{"A", "B", "C", "D", "A"} {
+		if answer := key.take(i + 1); !reflect.DeepEqual(answer, []string{letter}) {
+			t.Errorf("第%d题答案为%v，应为%s", i+1, answer, letter)
+		}
+	}
+}
+
+func TestSegmentAnswerKeyAfterBareHeading(t *testing.T) {
+	blocks, key := segmentScanLines(scanLinesOf(
+		"1. 甲",
+		"A. 是 B. 否",
+		"2. 乙",
+		"A. 是 B. 否",
+		"答案：",
+		"1.A 2.B",
+	))
+
+	if len(blocks) != 2 || blocks[1].answer != nil {
+		t.Fatalf("答案页被当作第2题的答案: %d %v", len(blocks), blocks[len(blocks)-1].answer)
+	}
+	if a, b := key.take(1), key.take(2); !reflect.DeepEqual(a, []string{"A"}) || !reflect.DeepEqual(b, []string{"B"}) {
+		t.Fatalf("答案页中的答案为 %v %v", a, b)
+	}
+}
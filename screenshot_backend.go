@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// screenshotFileEnv 指定截图文件的环境变量，设置后截图直接读取该文件，便于测试
+const screenshotFileEnv = "EXAM_SCREENSHOT_FILE"
+
+// screenshotTimeout 外部截图工具的超时时间
+const screenshotTimeout = 30 * time.Second
+
+// 截图来源状态
+var (
+	screenshotMu      sync.Mutex
+	screenshotFile    = os.Getenv(screenshotFileEnv) // 截图文件，为空时实际截取屏幕
+	lastScreenshotVia string                         // 最近一次截图成功使用的后端
+)
+
+// linuxScreenshotBackend Linux 下的截图后端
+type linuxScreenshotBackend struct {
+	name   string
+	binary string // 依赖的可执行文件，为空表示不依赖外部程序
+	// capture 截取整个桌面并保存为 PNG 文件，path 为可执行文件路径
+	capture func(ctx context.Context, path, file string) error
+}
+
+// linuxScreenshotBackends 按优先级排列的 Linux 截图后端
+// grim 适用于 wlroots 系合成器，gnome-screenshot 和 spectacle 分别适用于 GNOME 和 KDE，
+// 桌面门户适用于其余 Wayland 会话，import 仅适用于 X11
+var linuxScreenshotBackends = []linuxScreenshotBackend{
+	{name: "grim", binary: "grim", capture: func(ctx context.Context, path, file string) error {
+		return runScreenshotTool(ctx, path, "-t", "png", file)
+	}},
+	{name: "gnome-screenshot", binary: "gnome-screenshot", capture: func(ctx context.Context, path, file string) error {
+		return runScreenshotTool(ctx, path, "-f", file)
+	}},
+	{name: "spectacle", binary: "spectacle", capture: func(ctx context.Context, path, file string) error {
+		return runScreenshotTool(ctx, path, "-b", "-n", "-f", "-o", file)
+	}},
+	{name: "xdg-desktop-portal", capture: func(ctx context.Context, path, file string) error {
+		return capturePortalScreenshot(ctx, file)
+	}},
+	{name: "import", binary: "import", capture: func(ctx context.Context, path, file string) error {
+		return runScreenshotTool(ctx, path, "-window", "root", file)
+	}},
+}
+
+// runScreenshotTool 运行外部截图工具，失败时附带工具的错误输出
+func runScreenshotTool(ctx context.Context, path string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// sessionType 返回当前图形会话类型：wayland、x11 或空
+func sessionType() string {
+	if session := os.Getenv("XDG_SESSION_TYPE"); session == "wayland" || session == "x11" {
+		return session
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		return "wayland"
+	}
+	if os.Getenv("DISPLAY") != "" {
+		return "x11"
+	}
+	return ""
+}
+
+// portal 桌面门户截图接口
+const (
+	portalDestination = "org.freedesktop.portal.Desktop"
+	portalPath        = "/org/freedesktop/portal/desktop"
+)
+
+// portalAvailable 检查会话总线上是否有桌面门户服务
+func portalAvailable() error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("无法连接会话总线: %v", err)
+	}
+	defer conn.Close()
+
+	var owned bool
+	if err := conn.BusObject().Call("org.freedesktop.DBus.NameHasOwner", 0, portalDestination).Store(&owned); err == nil && owned {
+		return nil
+	}
+	var activatable []string
+	if err := conn.BusObject().Call("org.freedesktop.DBus.ListActivatableNames", 0).Store(&activatable); err == nil {
+		for _, name := range activatable {
+			if name == portalDestination {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("会话总线上没有 %s", portalDestination)
+}
+
+// capturePortalScreenshot 通过 xdg-desktop-portal 的 Screenshot 接口截取整个桌面
+// 门户将截图保存到用户目录，复制到 file 后删除原文件
+func capturePortalScreenshot(ctx context.Context, file string) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("无法连接会话总线: %v", err)
+	}
+	defer conn.Close()
+
+	// 请求对象路径由调用方的总线名称和 handle_token 决定，需在调用前订阅其 Response 信号
+	token := fmt.Sprintf("exam%d", time.Now().UnixNano())
+	sender := strings.ReplaceAll(strings.TrimPrefix(conn.Names()[0], ":"), ".", "_")
+	request := dbus.ObjectPath(portalPath + "/request/" + sender + "/" + token)
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(request),
+		dbus.WithMatchInterface("org.freedesktop.portal.Request"),
+		dbus.WithMatchMember("Response"),
+	); err != nil {
+		return fmt.Errorf("订阅门户响应失败: %v", err)
+	}
+	signals := make(chan *dbus.Signal, 1)
+	conn.Signal(signals)
+
+	options := map[string]dbus.Variant{
+		"handle_token": dbus.MakeVariant(token),
+		"interactive":  dbus.MakeVariant(false),
+	}
+	call := conn.Object(portalDestination, portalPath).CallWithContext(ctx, "org.freedesktop.portal.Screenshot.Screenshot", 0, "", options)
+	if call.Err != nil {
+		return fmt.Errorf("调用门户截图失败: %v", call.Err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("等待门户截图超时")
+		case signal := <-signals:
+			if signal.Path != request || len(signal.Body) < 2 {
+				continue
+			}
+			if code, _ := signal.Body[0].(uint32); code != 0 {
+				return fmt.Errorf("门户截图被拒绝或取消: %d", code)
+			}
+			results, _ := signal.Body[1].(map[string]dbus.Variant)
+			uri, _ := results["uri"].Value().(string)
+			parsed, err := url.Parse(uri)
+			if err != nil || parsed.Scheme != "file" {
+				return fmt.Errorf("门户返回的截图地址无效: %s", uri)
+			}
+
+			imageData, err := os.ReadFile(parsed.Path)
+			if err != nil {
+				return fmt.Errorf("读取门户截图失败: %v", err)
+			}
+			os.Remove(parsed.Path)
+			return os.WriteFile(file, imageData, 0644)
+		}
+	}
+}
+
+// check 检查截图后端是否可用，返回可执行文件路径
+func (b linuxScreenshotBackend) check() (string, error) {
+	if b.binary == "" {
+		return "", portalAvailable()
+	}
+	path, err := exec.LookPath(b.binary)
+	if err != nil {
+		return "", fmt.Errorf("未找到 %s", b.binary)
+	}
+	return path, nil
+}
+
+// captureLinuxScreenshot 依次尝试各截图后端截取整个桌面，返回 PNG 图片数据
+func captureLinuxScreenshot() ([]byte, error) {
+	temp, err := os.CreateTemp("", "screenshot-*.png")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	file := temp.Name()
+	temp.Close()
+	defer os.Remove(file)
+
+	var failures []string
+	for _, backend := range linuxScreenshotBackends {
+		path, err := backend.check()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", backend.name, err))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), screenshotTimeout)
+		err = backend.capture(ctx, path, file)
+		cancel()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", backend.name, err))
+			continue
+		}
+
+		imageData, err := os.ReadFile(file)
+		if err != nil || len(imageData) == 0 {
+			failures = append(failures, fmt.Sprintf("%s: 没有生成截图文件", backend.name))
+			continue
+		}
+
+		setLastScreenshotBackend(backend.name)
+		return imageData, nil
+	}
+
+	return nil, fmt.Errorf("没有可用的截图工具（%s）", strings.Join(failures, "；"))
+}
+
+// displayRect 返回显示器在整个桌面中的范围，以桌面左上角为原点
+func displayRect(display DisplayInfo, displays []DisplayInfo) image.Rectangle {
+	minX, minY, _, _ := virtualDesktopBounds(displays)
+	return image.Rect(display.X-minX, display.Y-minY, display.X-minX+display.Width, display.Y-minY+display.Height)
+}
+
+// cropToDisplay 从整个桌面的截图中裁剪出指定显示器
+// 截图与显示器物理尺寸不一致时（如 Wayland 的缩放输出）按比例换算
+func cropToDisplay(img image.Image, display DisplayInfo, displays []DisplayInfo) (image.Image, error) {
+	_, _, width, height := virtualDesktopBounds(displays)
+	if width == 0 || height == 0 {
+		return img, nil
+	}
+
+	bounds := img.Bounds()
+	scaleX := float64(bounds.Dx()) / float64(width)
+	scaleY := float64(bounds.Dy()) / float64(height)
+	target := displayRect(display, displays)
+	rect := image.Rect(
+		int(math.Round(float64(target.Min.X)*scaleX)),
+		int(math.Round(float64(target.Min.Y)*scaleY)),
+		int(math.Round(float64(target.Max.X)*scaleX)),
+		int(math.Round(float64(target.Max.Y)*scaleY)),
+	).Add(bounds.Min).Intersect(bounds)
+	if rect.Empty() {
+		return nil, fmt.Errorf("显示器不在截图范围内: %s", display.Name)
+	}
+
+	return cropImage(img, ScreenshotArea{X: rect.Min.X - bounds.Min.X, Y: rect.Min.Y - bounds.Min.Y, Width: rect.Dx(), Height: rect.Dy()}), nil
+}
+
+// takeLinuxScreenshot 截取 Linux 桌面，指定显示器时只保留该显示器
+// X11 会话下直接通过 X11 协议读取屏幕，失败时再依次尝试外部截图工具
+func (e *ExamService) takeLinuxScreenshot(displayID, displayName string) (image.Image, error) {
+	// 确定显示器，未能获取显示器信息或只有一个显示器时截取整个桌面
+	var display *DisplayInfo
+	var displays []DisplayInfo
+	if displayID != VirtualDesktopID {
+		if all, err := e.ListDisplays(); err == nil {
+			found, err := e.findDisplay(displayID, displayName)
+			if err != nil && displayID != "" {
+				return nil, err
+			}
+			if err == nil && len(all) > 1 {
+				display, displays = &found, all
+			}
+		}
+	}
+
+	if sessionType() == "x11" {
+		var rect image.Rectangle
+		if display != nil {
+			rect = displayRect(*display, displays)
+		}
+		img, err := captureX11(rect)
+		if err == nil {
+			setLastScreenshotBackend("x11")
+			return img, nil
+		}
+		log.Printf("X11截图失败，尝试外部截图工具: %v", err)
+	}
+
+	imageData, err := captureLinuxScreenshot()
+	if err != nil {
+		return nil, err
+	}
+	img, err := decodeImage(imageData)
+	if err != nil || display == nil {
+		return img, err
+	}
+	return cropToDisplay(img, *display, displays)
+}
+
+// setLastScreenshotBackend 记录最近一次截图成功使用的后端
+func setLastScreenshotBackend(name string) {
+	screenshotMu.Lock()
+	defer screenshotMu.Unlock()
+	lastScreenshotVia = name
+}
+
+// readScreenshotFile 读取PNG、JPEG或WebP图片文件作为截图
+func readScreenshotFile(path string) (image.Image, error) {
+	imageData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取截图文件失败: %v", err)
+	}
+	return decodeImage(imageData)
+}
+
+// LoadScreenshotFile 读取图片文件，返回与截图相同的PNG格式 data URL
+func (e *ExamService) LoadScreenshotFile(path string) (string, error) {
+	img, err := readScreenshotFile(path)
+	if err != nil {
+		return "", err
+	}
+	return imageDataURL(img)
+}
+
+// SetScreenshotFile 设置截图文件，设置后所有截图都读取该文件而不截取屏幕，path 为空时恢复截取屏幕
+func (e *ExamService) SetScreenshotFile(path string) error {
+	if path != "" {
+		if _, err := readScreenshotFile(path); err != nil {
+			return err
+		}
+	}
+
+	screenshotMu.Lock()
+	defer screenshotMu.Unlock()
+	screenshotFile = path
+	return nil
+}
+
+// ScreenshotBackendStatus 截图后端检测结果
+type ScreenshotBackendStatus struct {
+	Name      string `json:"name"`              // 后端名称
+	Path      string `json:"path,omitempty"`    // 可执行文件路径
+	Available bool   `json:"available"`         // 是否可用
+	Message   string `json:"message,omitempty"` // 不可用的原因或使用提示
+}
+
+// ScreenshotDiagnostics 截图环境诊断信息
+type ScreenshotDiagnostics struct {
+	OS          string                    `json:"os"`                    // 操作系统
+	Session     string                    `json:"session,omitempty"`     // 图形会话类型：wayland 或 x11
+	File        string                    `json:"file,omitempty"`        // 设置的截图文件
+	LastBackend string                    `json:"lastBackend,omitempty"` // 最近一次截图成功使用的后端
+	Backends    []ScreenshotBackendStatus `json:"backends"`              // 按尝试顺序排列的截图后端
+}
+
+// GetScreenshotDiagnostics 检测截图环境，列出各截图后端是否可用
+func (e *ExamService) GetScreenshotDiagnostics() ScreenshotDiagnostics {
+	screenshotMu.Lock()
+	diagnostics := ScreenshotDiagnostics{
+		OS:          runtime.GOOS,
+		Session:     sessionType(),
+		File:        screenshotFile,
+		LastBackend: lastScreenshotVia,
+		Backends:    []ScreenshotBackendStatus{},
+	}
+	screenshotMu.Unlock()
+
+	if runtime.GOOS != "linux" {
+		return diagnostics
+	}
+
+	// X11 会话下优先在进程内截图
+	x11 := ScreenshotBackendStatus{Name: "x11", Available: true}
+	if err := x11Available(); err != nil {
+		x11.Available, x11.Message = false, err.Error()
+	}
+	diagnostics.Backends = append(diagnostics.Backends, x11)
+
+	for _, backend := range linuxScreenshotBackends {
+		status := ScreenshotBackendStatus{Name: backend.name}
+		path, err := backend.check()
+		if err != nil {
+			status.Message = err.Error()
+		} else {
+			status.Path = path
+			status.Available = true
+			if backend.name == "import" && diagnostics.Session == "wayland" {
+				status.Message = "Wayland 会话下可能截图失败或返回黑屏"
+			}
+		}
+		diagnostics.Backends = append(diagnostics.Backends, status)
+	}
+	return diagnostics
+}
+
+// ScreenshotDiagnosticsResponse HTTP截图诊断响应结构
+type ScreenshotDiagnosticsResponse struct {
+	Success     bool                  `json:"success"`
+	Message     string                `json:"message,omitempty"`
+	Diagnostics ScreenshotDiagnostics `json:"diagnostics"`
+}
+
+// handleScreenshotDiagnostics 处理HTTP截图诊断请求
+// 截图文件只能通过环境变量或应用内的 SetScreenshotFile 设置，不通过HTTP接口修改
+func handleScreenshotDiagnostics(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 只允许GET方法
+	if r.Method != "GET" {
+		http.Error(w, "只支持GET方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	response := ScreenshotDiagnosticsResponse{
+		Success:     true,
+		Diagnostics: examService.GetScreenshotDiagnostics(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
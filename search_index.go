@@ -0,0 +1,262 @@
+package main
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxCandidates 倒排索引筛选出的候选题目上限，只有这些题目参与精确打分
+const maxCandidates = 200
+
+// gram 索引词元
+// 中文为相邻两个字符打包而成，只有一个字符时第二个字符为0；
+// 字母数字为整个单词的哈希值，最高位置1以与中文词元区分
+type gram uint64
+
+// wordGramFlag 字母数字词元的标记位
+const wordGramFlag = gram(1) << 63
+
+// newGram 将两个字符打包为词元
+func newGram(a, b rune) gram {
+	return gram(uint64(uint32(a))<<32 | uint64(uint32(b)))
+}
+
+// newWordGram 将字母数字单词转换为词元
+func newWordGram(word string) gram {
+	h := fnv.New64a()
+	h.Write([]byte(word))
+	return gram(h.Sum64()) | wordGramFlag
+}
+
+// posting 倒排表中的一项：题目序号及该词元在题目中出现的次数
+type posting struct {
+	doc  int32
+	freq int32
+}
+
+// searchIndex 题库的倒排索引
+// 中文按相邻两字切分（二元组），字母数字按单词切分，
+// 用于在精确打分前快速筛选出少量候选题目，并提供BM25排序所需的语料统计；
+// 另按单个字符记录出现过该字符的题目，查询与题库没有相同词元时用来筛选候选题目
+type searchIndex struct {
+	postings map[gram][]posting
+	chars    map[rune][]int32 // 单字符倒排表
+	docLens  []int            // 每道题目的词元数量
+	totalLen int              // 所有题目的词元总数
+}
+
+// newSearchIndex 为题目列表建立倒排索引
+func newSearchIndex(answers []AnswerItem) *searchIndex {
+	idx := &searchIndex{
+		postings: map[gram][]posting{},
+		chars:    map[rune][]int32{},
+		docLens:  make([]int, len(answers)),
+	}
+
+	var grams []gram
+	var chars []rune
+	for doc, answer := range answers {
+		text := answerIndexText(answer)
+		chars = uniqueChars(indexChars(text, chars[:0]))
+		for _, r := range chars {
+			idx.chars[r] = append(idx.chars[r], int32(doc))
+		}
+
+		grams = indexGrams(text, grams[:0])
+		idx.docLens[doc] = len(grams)
+		idx.totalLen += len(grams)
+
+		// 排序后相同词元相邻，顺序统计出现次数
+		sort.Slice(grams, func(i, j int) bool { return grams[i] < grams[j] })
+		for i := 0; i < len(grams); {
+			j := i + 1
+			for j < len(grams) && grams[j] == grams[i] {
+				j++
+			}
+			idx.postings[grams[i]] = append(idx.postings[grams[i]], posting{doc: int32(doc), freq: int32(j - i)})
+			i = j
+		}
+	}
+
+	return idx
+}
+
+// answerIndexText 返回题目中参与索引的全部文本
+func answerIndexText(answer AnswerItem) string {
+	parts := []string{answer.Question}
+	parts = append(parts, answer.Options...)
+	parts = append(parts, answer.Answer...)
+	parts = append(parts, answer.Explanation, answer.Source)
+	parts = append(parts, answer.Tags...)
+	return strings.Join(parts, " ")
+}
+
+// indexGrams 将文本切分为索引词元并追加到 grams
+// 文本先经过与搜索相同的标准化，中文连续段取相邻两字组成的二元组，只有一个字时直接作为词元；
+// 字母和数字连续段整体作为一个词元
+func indexGrams(text string, grams []gram) []gram {
+	text = strings.ToLower((&ExamService{}).normalizeText(text))
+
+	han := []rune{}
+	word := []rune{}
+	flush := func() {
+		if len(han) == 1 {
+			grams = append(grams, newGram(han[0], 0))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			grams = append(grams, newGram(han[i], han[i+1]))
+		}
+		if len(word) > 0 {
+			grams = append(grams, newWordGram(string(word)))
+		}
+		han, word = han[:0], word[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if len(word) > 0 {
+				flush()
+			}
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(han) > 0 {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return grams
+}
+
+// indexChars 将文本中的中文、字母和数字逐字追加到 chars，文本先经过与 indexGrams 相同的标准化
+func indexChars(text string, chars []rune) []rune {
+	text = strings.ToLower((&ExamService{}).normalizeText(text))
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			chars = append(chars, r)
+		}
+	}
+	return chars
+}
+
+// uniqueChars 原地去除重复的字符，不保持原有顺序
+func uniqueChars(chars []rune) []rune {
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	unique := chars[:0]
+	for i, r := range chars {
+		if i == 0 || r != chars[i-1] {
+			unique = append(unique, r)
+		}
+	}
+	return unique
+}
+
+// candidates 返回与查询共享词元最多的题目序号，按命中词元数从高到低排列
+// keep 不为 nil 时只保留满足条件的题目，在截取前 limit 个之前筛选，避免符合筛选条件的题目被截掉
+// 查询无法切分出词元时返回 nil，表示需要全量搜索
+func (idx *searchIndex) candidates(query string, limit int, keep func(doc int) bool) []int {
+	grams := uniqueGrams(indexGrams(query, nil))
+	if len(grams) == 0 {
+		return nil
+	}
+
+	hits := make([]int32, len(idx.docLens))
+	for _, g := range grams {
+		for _, p := range idx.postings[g] {
+			hits[p.doc]++
+		}
+	}
+	return topHits(hits, len(grams), limit, keep)
+}
+
+// charCandidates 返回与查询共享字符最多的题目序号，用法与 candidates 相同
+// 用于查询与题库没有相同词元（如字形相近的OCR错字）时筛选候选题目，耗时只与命中字符的倒排表长度有关
+func (idx *searchIndex) charCandidates(query string, limit int, keep func(doc int) bool) []int {
+	chars := uniqueChars(indexChars(query, nil))
+	if len(chars) == 0 {
+		return nil
+	}
+
+	hits := make([]int32, len(idx.docLens))
+	for _, r := range chars {
+		for _, doc := range idx.chars[r] {
+			hits[doc]++
+		}
+	}
+	return topHits(hits, len(chars), limit, keep)
+}
+
+// topHits 按命中数分桶，从高到低取出满足 keep 的前 limit 个题目序号，maxHits 为可能的最大命中数
+func topHits(hits []int32, maxHits, limit int, keep func(doc int) bool) []int {
+	buckets := make([][]int, maxHits+1)
+	for doc, n := range hits {
+		if n > 0 && (keep == nil || keep(doc)) {
+			buckets[n] = append(buckets[n], doc)
+		}
+	}
+
+	matched := []int{}
+	for n := maxHits; n > 0 && len(matched) < limit; n-- {
+		matched = append(matched, buckets[n]...)
+	}
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched
+}
+
+// uniqueGrams 去除重复的词元，保持原有顺序
+func uniqueGrams(grams []gram) []gram {
+	seen := map[gram]bool{}
+	unique := []gram{}
+	for _, g := range grams {
+		if !seen[g] {
+			seen[g] = true
+			unique = append(unique, g)
+		}
+	}
+	return unique
+}
+
+// shortlist 使用倒排索引筛选出满足标签、难度和来源条件的候选题目
+// 没有题目与查询共享词元时改按单个字符筛选，以免只靠字形相近或模糊匹配才能找到的题目被漏掉；
+// 仍没有命中时取前 maxCandidates 道满足条件的题目，保证耗时不随题库规模增长。
+// 索引不可用或查询无法切分出词元（如空查询）时返回全部满足条件的题目
+func (b storedBank) shortlist(query string, filters SearchFilters) []AnswerItem {
+	keep := func(doc int) bool { return filters.matchItem(b.Answers[doc]) }
+	limit := len(b.Answers)
+
+	var docs []int
+	if b.index != nil && len(indexGrams(query, nil)) > 0 {
+		limit = maxCandidates
+		docs = b.index.candidates(query, limit, keep)
+		if len(docs) == 0 {
+			docs = b.index.charCandidates(query, limit, keep)
+		}
+	}
+	if len(docs) == 0 {
+		answers := []AnswerItem{}
+		for _, answer := range b.Answers {
+			if len(answers) >= limit {
+				break
+			}
+			if filters.matchItem(answer) {
+				answers = append(answers, answer)
+			}
+		}
+		return answers
+	}
+
+	answers := make([]AnswerItem, 0, len(docs))
+	for _, doc := range docs {
+		answers = append(answers, b.Answers[doc])
+	}
+	return answers
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generateAnswers 从常用汉字中随机生成 n 道题目，内容由固定种子决定
+func generateAnswers(n int) []AnswerItem {
+	r := rand.New(rand.NewSource(1))
+	chars := []rune(commonSimplified)
+	text := func(length int) string {
+		var b strings.Builder
+		for i := 0; i < length; i++ {
+			b.WriteRune(chars[r.Intn(len(chars))])
+		}
+		return b.String()
+	}
+
+	answers := make([]AnswerItem, n)
+	for i := range answers {
+		answers[i] = AnswerItem{
+			Type:     QuestionTypeSingle,
+			Question: fmt.Sprintf("%s第%d题", text(20+r.Intn(20)), i),
+			Options:  []string{text(6), text(6), text(6), text(6)},
+			Answer:   []string{"A"},
+			Tags:     []string{fmt.Sprintf("第%d章", i%10)},
+		}
+	}
+	return answers
+}
+
+// useTestStore 将全局题库替换为临时目录中的题库，测试结束后恢复
+func useTestStore(tb testing.TB, answers []AnswerItem) storedBank {
+	previous := answerStore
+	answerStore = NewAnswerStore(filepath.Join(tb.TempDir(), storeFileName))
+	tb.Cleanup(func() { answerStore = previous })
+
+	if _, err := answerStore.AddBank("测试题库", "", answers); err != nil {
+		tb.Fatal(err)
+	}
+	return answerStore.EnabledBanks()[0]
+}
+
+func TestShortlistContainsExactMatch(t *testing.T) {
+	answers := generateAnswers(5000)
+	bank := useTestStore(t, answers)
+
+	for _, i := range []int{0, 1234, 4999} {
+		query := answers[i].Question
+		found := false
+		for _, answer := range bank.shortlist(query, SearchFilters{}) {
+			if answer.Question == query {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("候选题目中没有完全匹配的第%d题: %s", i, query)
+		}
+	}
+}
+
+func TestShortlistAppliesFiltersBeforeLimit(t *testing.T) {
+	answers := generateAnswers(5000)
+	bank := useTestStore(t, answers)
+
+	// 只按标签筛选时，候选题目应全部来自该标签，且数量不因其他题目占满上限而减少
+	filters := SearchFilters{Tags: []string{"第3章"}}
+	shortlist := bank.shortlist(answers[3].Question, filters)
+	if len(shortlist) < maxCandidates {
+		t.Fatalf("候选题目数量为%d，应为%d", len(shortlist), maxCandidates)
+	}
+	for _, answer := range shortlist {
+		if !filters.matchItem(answer) {
+			t.Fatalf("候选题目不满足筛选条件: %v", answer.Tags)
+		}
+	}
+}
+
+func TestShortlistFallsBackWithoutSharedGrams(t *testing.T) {
+	bank := useTestStore(t, []AnswerItem{{Question: "安全生产管理制度", Options: []string{}, Answer: []string{}}})
+
+	// 查询与题目没有相同的二元组，但仍应参与模糊匹配
+	if shortlist := bank.shortlist("安仝生严", SearchFilters{}); len(shortlist) != 1 {
+		t.Fatalf("候选题目数量为%d，应为1", len(shortlist))
+	}
+}
+
+func TestSearchBM25AppliesFiltersBeforeLimit(t *testing.T) {
+	answers := []AnswerItem{}
+	for i := 0; i < 300; i++ {
+		answers = append(answers, AnswerItem{
+			Question: fmt.Sprintf("安全生产管理制度第%d条", i),
+			Options:  []string{},
+			Answer:   []string{},
+			Tags:     []string{"其他"},
+		})
+	}
+	// 题目较长，BM25 分数低于上面的300道题目
+	answers = append(answers, AnswerItem{
+		Question: "企业应当建立健全安全生产管理制度并定期组织开展应急演练和隐患排查",
+		Options:  []string{},
+		Answer:   []string{},
+		Tags:     []string{"目标"},
+	})
+	useTestStore(t, answers)
+	discardSearchLogs(t)
+
+	results, err := (&ExamService{}).SearchBanks("安全生产管理制度", SearchFilters{Ranking: RankingBM25, Tags: []string{"目标"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Item.Tags[0] != "目标" {
+		t.Fatalf("搜索结果为%d条，应只有标签为目标的1条", len(results))
+	}
+}
+
+func TestShortlistBoundedWithoutSharedChars(t *testing.T) {
+	answers := generateAnswers(1000)
+	bank := useTestStore(t, answers)
+
+	// 查询与题库没有任何相同字符时只取有限数量的题目，避免全量打分
+	if shortlist := bank.shortlist("qwerty zxcv", SearchFilters{}); len(shortlist) != maxCandidates {
+		t.Fatalf("候选题目数量为%d，应为%d", len(shortlist), maxCandidates)
+	}
+}
+
+// searchBenchmarkQueries 返回在 generateAnswers 题库上计时用的查询，按是否与题库共享词元分组
+func searchBenchmarkQueries(answers []AnswerItem) []struct {
+	name    string
+	queries []string
+} {
+	return []struct {
+		name    string
+		queries []string
+	}{
+		{"SharedGrams", []string{answers[100].Question, answers[25000].Question, answers[49999].Question[:30]}},
+		// 字母数字查询或严重错误的OCR结果与题库没有相同的词元
+		{"NoSharedGrams", []string{"qwerty zxcv", "1234567"}},
+	}
+}
+
+// discardSearchLogs 搜索过程会逐条记录日志，计时只关心搜索本身的耗时
+func discardSearchLogs(tb testing.TB) {
+	log.SetOutput(io.Discard)
+	tb.Cleanup(func() { log.SetOutput(os.Stderr) })
+}
+
+func TestSearchBanksLatency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("建立5万道题目的题库耗时较长")
+	}
+	answers := generateAnswers(50000)
+	useTestStore(t, answers)
+	discardSearchLogs(t)
+	e := &ExamService{}
+
+	// 预热一次后计时，上限相对目标100毫秒留有余量，以免机器负载波动导致失败
+	const rounds = 5
+	const limit = 500 * time.Millisecond
+	for _, c := range searchBenchmarkQueries(answers) {
+		for _, query := range c.queries {
+			if _, err := e.SearchBanks(query, SearchFilters{}); err != nil {
+				t.Fatal(err)
+			}
+			start := time.Now()
+			for i := 0; i < rounds; i++ {
+				e.SearchBanks(query, SearchFilters{})
+			}
+			if perQuery := time.Since(start) / rounds; perQuery > limit {
+				t.Errorf("%s: 查询%q每次耗时%v，超过%v", c.name, query, perQuery, limit)
+			}
+		}
+	}
+}
+
+func BenchmarkSearchBanks(b *testing.B) {
+	answers := generateAnswers(50000)
+	useTestStore(b, answers)
+	discardSearchLogs(b)
+	e := &ExamService{}
+
+	for _, c := range searchBenchmarkQueries(answers) {
+		b.Run(c.name, func(b *testing.B) {
+			// 建立题库产生的垃圾不计入搜索耗时
+			runtime.GC()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := e.SearchBanks(c.queries[i%len(c.queries)], SearchFilters{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Microseconds())/1000/float64(b.N), "ms/query")
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// builtinDictionary 内置中文分词词典
+//
+//go:embed dict/zh_dict.txt
+var builtinDictionary string
+
+// userDictionaryDir 用户词典目录，目录下所有 .txt 文件都会被加载
+const userDictionaryDir = "dict"
+
+// userDictionaryFile 通过接口维护的用户词典文件名
+const userDictionaryFile = "user_dict.txt"
+
+// userWordFreq 用户词典中未指定词频的词语使用的词频，高于内置词典以保证领域术语优先切分
+const userWordFreq = 10000
+
+// Tokenizer 分词器，将文本切分为用于关键词匹配的词语
+type Tokenizer interface {
+	Tokenize(text string) []string
+}
+
+// 分词器名称
+const (
+	TokenizerWhitespace = "whitespace" // 按空白切分
+	TokenizerDictionary = "dictionary" // 基于词典的中文分词（默认）
+)
+
+var (
+	tokenizerMu sync.RWMutex
+	// 已注册的分词器
+	tokenizers = map[string]Tokenizer{
+		TokenizerWhitespace: whitespaceTokenizer{},
+		TokenizerDictionary: NewDictTokenizer(strings.NewReader(builtinDictionary)),
+	}
+	// 搜索使用的分词器
+	searchTokenizer = tokenizers[TokenizerDictionary]
+)
+
+// RegisterTokenizer 注册分词器，同名分词器会被替换
+func RegisterTokenizer(name string, tokenizer Tokenizer) {
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+	tokenizers[name] = tokenizer
+}
+
+// currentTokenizer 返回搜索使用的分词器
+func currentTokenizer() Tokenizer {
+	tokenizerMu.RLock()
+	defer tokenizerMu.RUnlock()
+	return searchTokenizer
+}
+
+// whitespaceTokenizer 按空白切分文本
+type whitespaceTokenizer struct{}
+
+func (whitespaceTokenizer) Tokenize(text string) []string {
+	return strings.Fields(text)
+}
+
+// DictTokenizer 基于词典的中文分词器
+// 中文连续段在词典构成的所有切分方式中选择词频概率乘积最大的一种，
+// 字母和数字连续段整体作为一个词
+type DictTokenizer struct {
+	logProb    map[string]float64 // 词语 -> 对数概率
+	unknown    float64            // 未登录单字的对数概率
+	maxWordLen int                // 词典中最长词语的字数
+}
+
+// NewDictTokenizer 从词典读取器创建分词器
+// 词典每行一个词，可在词语后以空白分隔词频；以 # 开头的行为注释
+func NewDictTokenizer(readers ...io.Reader) *DictTokenizer {
+	freqs := map[string]float64{}
+	for _, r := range readers {
+		readDictionary(r, freqs)
+	}
+
+	total := 0.0
+	for _, freq := range freqs {
+		total += freq
+	}
+	if total == 0 {
+		total = 1
+	}
+
+	t := &DictTokenizer{
+		logProb: make(map[string]float64, len(freqs)),
+		unknown: math.Log(1 / total),
+	}
+	for word, freq := range freqs {
+		t.logProb[word] = math.Log(freq / total)
+		if n := len([]rune(word)); n > t.maxWordLen {
+			t.maxWordLen = n
+		}
+	}
+	return t
+}
+
+// readDictionary 读取词典内容，同一词语多次出现时取最大词频
+func readDictionary(r io.Reader, freqs map[string]float64) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		word := strings.ToLower(fields[0])
+		freq := float64(userWordFreq)
+		if len(fields) > 1 {
+			if f, err := strconv.ParseFloat(fields[1], 64); err == nil && f > 0 {
+				freq = f
+			}
+		}
+		if freq > freqs[word] {
+			freqs[word] = freq
+		}
+	}
+}
+
+// Tokenize 切分文本，标点和空白作为分隔符
+func (t *DictTokenizer) Tokenize(text string) []string {
+	tokens := []string{}
+	han := []rune{}
+	word := []rune{}
+	flush := func() {
+		if len(han) > 0 {
+			tokens = append(tokens, t.segment(han)...)
+		}
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+		}
+		han, word = han[:0], word[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if len(word) > 0 {
+				flush()
+			}
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(han) > 0 {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// segment 对中文连续段做最大概率切分
+func (t *DictTokenizer) segment(runes []rune) []string {
+	n := len(runes)
+	text := string(runes)
+
+	// offsets[i] 为第 i 个字在 text 中的字节位置，用于截取子串时避免重复分配
+	offsets := make([]int, n+1)
+	for i, r := range runes {
+		offsets[i+1] = offsets[i] + utf8.RuneLen(r)
+	}
+
+	// best[i] 为 runes[i:] 的最大对数概率，next[i] 为从 i 开始的词语结束位置
+	best := make([]float64, n+1)
+	next := make([]int, n+1)
+	for i := n - 1; i >= 0; i-- {
+		best[i] = t.unknown + best[i+1]
+		next[i] = i + 1
+		for j := i + 2; j <= n && j-i <= t.maxWordLen; j++ {
+			if p, ok := t.logProb[text[offsets[i]:offsets[j]]]; ok && p+best[j] >= best[i] {
+				best[i] = p + best[j]
+				next[i] = j
+			}
+		}
+	}
+
+	// 连续的未登录单字合并为一个词，通常是词典未收录的新词或OCR识别错误的词
+	words := []string{}
+	unknownStart := -1
+	for i := 0; i < n; i = next[i] {
+		_, known := t.logProb[text[offsets[i]:offsets[next[i]]]]
+		if next[i] == i+1 && !known {
+			if unknownStart < 0 {
+				unknownStart = i
+			}
+			continue
+		}
+		if unknownStart >= 0 {
+			words = append(words, text[offsets[unknownStart]:offsets[i]])
+			unknownStart = -1
+		}
+		words = append(words, text[offsets[i]:offsets[next[i]]])
+	}
+	if unknownStart >= 0 {
+		words = append(words, text[offsets[unknownStart]:])
+	}
+	return words
+}
+
+// userDictionaryPath 返回用户词典文件路径
+func userDictionaryPath() string {
+	return filepath.Join(appConfigDir(), userDictionaryDir, userDictionaryFile)
+}
+
+// loadDictionaries 加载内置词典和用户词典目录下的所有词典，重建默认分词器
+func loadDictionaries() error {
+	readers := []io.Reader{strings.NewReader(builtinDictionary)}
+
+	paths, _ := filepath.Glob(filepath.Join(appConfigDir(), userDictionaryDir, "*.txt"))
+	sort.Strings(paths)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取用户词典失败: %v", err)
+		}
+		readers = append(readers, strings.NewReader(string(content)))
+	}
+
+	tokenizer := NewDictTokenizer(readers...)
+
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+	// 当前正在使用默认分词器时一并切换
+	if searchTokenizer == tokenizers[TokenizerDictionary] {
+		searchTokenizer = tokenizer
+	}
+	tokenizers[TokenizerDictionary] = tokenizer
+	return nil
+}
+
+// loadUserDictionaries 启动时加载用户词典
+func loadUserDictionaries() {
+	if err := loadDictionaries(); err != nil {
+		log.Printf("加载用户词典失败: %v", err)
+	}
+}
+
+// ListTokenizers 获取所有已注册的分词器名称
+func (e *ExamService) ListTokenizers() []string {
+	tokenizerMu.RLock()
+	defer tokenizerMu.RUnlock()
+
+	names := make([]string, 0, len(tokenizers))
+	for name := range tokenizers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTokenizer 切换搜索使用的分词器
+func (e *ExamService) SetTokenizer(name string) error {
+	tokenizerMu.Lock()
+	defer tokenizerMu.Unlock()
+
+	tokenizer, ok := tokenizers[name]
+	if !ok {
+		return fmt.Errorf("分词器不存在: %s", name)
+	}
+	searchTokenizer = tokenizer
+	return nil
+}
+
+// GetUserDictionary 获取用户词典中的词语
+func (e *ExamService) GetUserDictionary() ([]string, error) {
+	content, err := os.ReadFile(userDictionaryPath())
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取用户词典失败: %v", err)
+	}
+
+	words := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words, nil
+}
+
+// SetUserDictionary 保存用户词典并立即生效
+// 每项为一个词语，可在词语后以空格分隔词频
+func (e *ExamService) SetUserDictionary(words []string) error {
+	lines := []string{}
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			lines = append(lines, word)
+		}
+	}
+
+	content := strings.Join(lines, "\n") + "\n"
+	if err := writeFileAtomic(userDictionaryPath(), []byte(content)); err != nil {
+		return err
+	}
+	return loadDictionaries()
+}
+
+// UserDictionaryRequest HTTP用户词典请求结构
+type UserDictionaryRequest struct {
+	Words []string `json:"words"`
+}
+
+// UserDictionaryResponse HTTP用户词典响应结构
+type UserDictionaryResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Words   []string `json:"words,omitempty"`
+}
+
+// handleUserDictionary 处理HTTP用户词典请求
+// GET 获取用户词典，POST 保存用户词典
+func handleUserDictionary(w http.ResponseWriter, r *http.Request) {
+	// 设置CORS头
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	// 处理预检请求
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// 创建ExamService实例
+	examService := &ExamService{}
+
+	var err error
+	switch r.Method {
+	case "GET":
+	case "POST":
+		var req UserDictionaryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "请求体解析失败: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = examService.SetUserDictionary(req.Words)
+	default:
+		http.Error(w, "只支持GET和POST方法", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var words []string
+	if err == nil {
+		words, err = examService.GetUserDictionary()
+	}
+
+	response := UserDictionaryResponse{Success: err == nil, Words: words}
+	if err != nil {
+		response.Message = "用户词典操作失败: " + err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"math/bits"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// captureX11 通过 X11 协议直接读取根窗口的像素，rect 为空时截取整个根窗口
+// 连接 $DISPLAY 指定的显示服务器，不依赖外部程序和临时文件
+func captureX11(rect image.Rectangle) (*image.RGBA, error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return nil, fmt.Errorf("连接X11显示服务器失败: %v", err)
+	}
+	defer conn.Close()
+
+	setup := xproto.Setup(conn)
+	screen := setup.DefaultScreen(conn)
+
+	root := image.Rect(0, 0, int(screen.WidthInPixels), int(screen.HeightInPixels))
+	if rect.Empty() {
+		rect = root
+	}
+	rect = rect.Intersect(root)
+	if rect.Empty() {
+		return nil, fmt.Errorf("截图区域不在屏幕范围内")
+	}
+
+	// 只支持每像素32位的真彩色格式，即常见的24位和32位色深
+	bitsPerPixel := 0
+	for _, format := range setup.PixmapFormats {
+		if format.Depth == screen.RootDepth {
+			bitsPerPixel = int(format.BitsPerPixel)
+		}
+	}
+	if bitsPerPixel != 32 {
+		return nil, fmt.Errorf("不支持的像素格式: 色深%d，每像素%d位", screen.RootDepth, bitsPerPixel)
+	}
+
+	var visual *xproto.VisualInfo
+	for _, depth := range screen.AllowedDepths {
+		for i := range depth.Visuals {
+			if depth.Visuals[i].VisualId == screen.RootVisual {
+				visual = &depth.Visuals[i]
+			}
+		}
+	}
+	if visual == nil || visual.Class != xproto.VisualClassTrueColor {
+		return nil, fmt.Errorf("根窗口不是真彩色视觉类型")
+	}
+
+	reply, err := xproto.GetImage(conn, xproto.ImageFormatZPixmap, xproto.Drawable(screen.Root),
+		int16(rect.Min.X), int16(rect.Min.Y), uint16(rect.Dx()), uint16(rect.Dy()), 0xffffffff).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("读取屏幕图像失败: %v", err)
+	}
+
+	width, height := rect.Dx(), rect.Dy()
+	if len(reply.Data) < width*height*4 {
+		return nil, fmt.Errorf("屏幕图像数据不完整: %d字节", len(reply.Data))
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if setup.ImageByteOrder == xproto.ImageOrderMSBFirst {
+		order = binary.BigEndian
+	}
+	redShift, greenShift, blueShift := maskShift(visual.RedMask), maskShift(visual.GreenMask), maskShift(visual.BlueMask)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < width*height; i++ {
+		pixel := order.Uint32(reply.Data[i*4:])
+		img.Pix[i*4] = uint8(pixel & visual.RedMask >> redShift)
+		img.Pix[i*4+1] = uint8(pixel & visual.GreenMask >> greenShift)
+		img.Pix[i*4+2] = uint8(pixel & visual.BlueMask >> blueShift)
+		img.Pix[i*4+3] = 0xff
+	}
+	return img, nil
+}
+
+// maskShift 返回颜色掩码对应的8位通道需要右移的位数
+func maskShift(mask uint32) int {
+	if mask == 0 {
+		return 0
+	}
+	return max(bits.TrailingZeros32(mask)+bits.OnesCount32(mask)-8, 0)
+}
+
+// x11Available 检查能否连接X11显示服务器
+func x11Available() error {
+	if sessionType() != "x11" {
+		return fmt.Errorf("当前不是X11会话")
+	}
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return fmt.Errorf("连接X11显示服务器失败: %v", err)
+	}
+	conn.Close()
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"image"
+	"image/color"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// startXvfb 启动黑色背景的 Xvfb 并将 DISPLAY 指向它，测试结束后关闭；没有安装 Xvfb 时跳过测试
+func startXvfb(t *testing.T, width, height string) {
+	t.Helper()
+	path, err := exec.LookPath("Xvfb")
+	if err != nil {
+		t.Skip("未安装 Xvfb")
+	}
+
+	// 通过 -displayfd 让 Xvfb 自己选择空闲的显示编号
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	cmd := exec.Command(path, "-displayfd", "3", "-screen", "0", width+"x"+height+"x24", "-br", "-nolisten", "tcp")
+	cmd.ExtraFiles = []*os.File{writer}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("启动 Xvfb 失败: %v", err)
+	}
+	writer.Close()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	number, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil {
+		t.Fatalf("读取 Xvfb 显示编号失败: %v", err)
+	}
+	t.Setenv("DISPLAY", ":"+strings.TrimSpace(number))
+
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		conn, err := xgb.NewConn()
+		if err == nil {
+			conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("连接 Xvfb 失败: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// x11Fill 一块要在根窗口上填充的纯色矩形
+type x11Fill struct {
+	rect    xproto.Rectangle
+	r, g, b uint16
+}
+
+// fillX11Root 在根窗口上填充纯色矩形，颜色由 X 服务器分配像素值，与截图的通道解码相互独立
+func fillX11Root(t *testing.T, fills []x11Fill) {
+	t.Helper()
+	conn, err := xgb.NewConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	screen := xproto.Setup(conn).DefaultScreen(conn)
+	for _, fill := range fills {
+		allocated, err := xproto.AllocColor(conn, screen.DefaultColormap, fill.r, fill.g, fill.b).Reply()
+		if err != nil {
+			t.Fatalf("分配颜色失败: %v", err)
+		}
+		gc, err := xproto.NewGcontextId(conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := xproto.CreateGCChecked(conn, gc, xproto.Drawable(screen.Root), xproto.GcForeground, []uint32{allocated.Pixel}).Check(); err != nil {
+			t.Fatalf("创建图形上下文失败: %v", err)
+		}
+		if err := xproto.PolyFillRectangleChecked(conn, xproto.Drawable(screen.Root), gc, []xproto.Rectangle{fill.rect}).Check(); err != nil {
+			t.Fatalf("填充矩形失败: %v", err)
+		}
+		xproto.FreeGC(conn, gc)
+	}
+}
+
+// fillPrimaries 在根窗口顶部从左到右填充红、绿、蓝三个 200x200 的矩形，其余部分保持黑色
+func fillPrimaries(t *testing.T) {
+	fillX11Root(t, []x11Fill{
+		{xproto.Rectangle{X: 0, Y: 0, Width: 200, Height: 200}, 0xffff, 0, 0},
+		{xproto.Rectangle{X: 200, Y: 0, Width: 200, Height: 200}, 0, 0xffff, 0},
+		{xproto.Rectangle{X: 400, Y: 0, Width: 200, Height: 200}, 0, 0, 0xffff},
+	})
+}
+
+// checkPixels 检查截图中各点的颜色
+func checkPixels(t *testing.T, img *image.RGBA, want map[image.Point]color.RGBA) {
+	t.Helper()
+	for p, c := range want {
+		if got := img.RGBAAt(p.X, p.Y); got != c {
+			t.Errorf("像素%v为%v，应为%v", p, got, c)
+		}
+	}
+}
+
+var (
+	x11Red   = color.RGBA{0xff, 0, 0, 0xff}
+	x11Green = color.RGBA{0, 0xff, 0, 0xff}
+	x11Blue  = color.RGBA{0, 0, 0xff, 0xff}
+	x11Black = color.RGBA{0, 0, 0, 0xff}
+)
+
+func TestCaptureX11RootWindow(t *testing.T) {
+	startXvfb(t, "640", "480")
+	fillPrimaries(t)
+
+	img, err := captureX11(image.Rectangle{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(640, 480) {
+		t.Fatalf("截图尺寸为%v，应为640x480", size)
+	}
+
+	// 未填充的部分因 -br 保持黑色
+	checkPixels(t, img, map[image.Point]color.RGBA{
+		{0, 0}:     x11Red,
+		{199, 199}: x11Red,
+		{200, 0}:   x11Green,
+		{300, 100}: x11Green,
+		{400, 0}:   x11Blue,
+		{599, 199}: x11Blue,
+		{600, 0}:   x11Black,
+		{320, 240}: x11Black,
+		{639, 479}: x11Black,
+	})
+}
+
+func TestCaptureX11Region(t *testing.T) {
+	startXvfb(t, "640", "480")
+	fillPrimaries(t)
+
+	// 区域原点不为零时，截图坐标相对区域左上角
+	img, err := captureX11(image.Rect(150, 100, 450, 300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := img.Bounds().Size(); size != image.Pt(300, 200) {
+		t.Fatalf("截图尺寸为%v，应为300x200", size)
+	}
+	checkPixels(t, img, map[image.Point]color.RGBA{
+		{0, 0}:     x11Red,
+		{49, 99}:   x11Red,
+		{50, 0}:    x11Green,
+		{249, 99}:  x11Green,
+		{250, 0}:   x11Blue,
+		{299, 99}:  x11Blue,
+		{0, 100}:   x11Black,
+		{299, 199}: x11Black,
+	})
+
+	img, err = captureX11(image.Rect(600, 20, 700, 70))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 超出屏幕的部分被裁掉
+	if size := img.Bounds().Size(); size != image.Pt(40, 50) {
+		t.Fatalf("截图尺寸为%v，应为40x50", size)
+	}
+
+	if _, err := captureX11(image.Rect(800, 600, 900, 700)); err == nil {
+		t.Fatal("屏幕范围外的区域应返回错误")
+	}
+}